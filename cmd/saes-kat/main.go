@@ -0,0 +1,119 @@
+// Command saes-kat 使用内置（或指定的）已知答案测试向量校验 S-AES 实现。
+//
+// 用法：
+//
+//	saes-kat [-engine reference]            校验本仓库的实现
+//	saes-kat -exec ./impl [-- 额外参数...]   按命令行约定校验外部程序
+//	saes-kat -http http://host/kat          按 HTTP 约定校验外部服务
+//	saes-kat -serve :9000                   以 HTTP 约定提供本仓库的参考实现
+//	saes-kat -dump                          输出内置向量文件
+//
+// 命令行约定：每个用例执行一次 `impl [额外参数...] OP ARGS...`，标准输出首行为结果，
+// 退出码 2 表示不支持该操作。HTTP 约定：POST {"op": OP, "args": [...]}，
+// 返回 {"result": "..."}，不支持时返回 501 或 {"unsupported": true}。
+//
+// 操作：
+//
+//	block-encrypt KEY PLAINTEXT                   -> CIPHERTEXT（16 位十六进制，如 0x0738）
+//	block-decrypt KEY CIPHERTEXT                  -> PLAINTEXT
+//	expand-key KEY                                -> 以空格分隔的轮密钥
+//	encrypt MODE PADDING KEY IV PLAINTEXT_HEX     -> CIPHERTEXT_HEX（IV 为 "-" 表示不使用）
+//	decrypt MODE PADDING KEY IV CIPHERTEXT_HEX    -> PLAINTEXT_HEX
+//
+// KEY 为 16、32 或 48 位十六进制密钥，后两者表示二重/三重级联加密。
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"S-AES/utils/kat"
+	"S-AES/utils/saes"
+)
+
+func main() {
+	var (
+		vectorsPath = flag.String("vectors", "", "测试向量 JSON 文件（默认使用内置向量）")
+		engine      = flag.String("engine", "reference", "校验本仓库时使用的实现：reference、fast、bitslice、constant-time")
+		execPath    = flag.String("exec", "", "按命令行约定校验的外部程序，flag 之后的参数会放在操作名之前传给它")
+		httpURL     = flag.String("http", "", "按 HTTP 约定校验的服务地址")
+		serveAddr   = flag.String("serve", "", "以 HTTP 约定提供参考实现的监听地址")
+		dump        = flag.Bool("dump", false, "输出内置测试向量后退出")
+		timeout     = flag.Duration("timeout", 10*time.Second, "单个用例的超时时间")
+		verbose     = flag.Bool("v", false, "逐个输出失败用例的详细信息")
+	)
+	flag.Parse()
+
+	if *dump {
+		os.Stdout.Write(kat.Raw())
+		return
+	}
+
+	bc, err := engineByName(*engine)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *serveAddr != "" {
+		log.Printf("以 HTTP 约定在 %s 提供参考实现（%s）", *serveAddr, *engine)
+		log.Fatal(http.ListenAndServe(*serveAddr, kat.Handler(kat.Reference(bc))))
+	}
+
+	vectors, err := loadVectors(*vectorsPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var impl kat.Implementation
+	target := "本仓库实现（" + *engine + "）"
+	switch {
+	case *execPath != "" && *httpURL != "":
+		log.Fatal("-exec 与 -http 不能同时使用")
+	case *execPath != "":
+		impl = kat.Command(*execPath, flag.Args(), *timeout)
+		target = *execPath
+	case *httpURL != "":
+		impl = kat.HTTP(*httpURL, *timeout)
+		target = *httpURL
+	default:
+		impl = kat.Reference(bc)
+	}
+
+	report := kat.Run(impl, vectors.Cases())
+	for _, f := range report.Failures {
+		if f.Err != nil {
+			fmt.Printf("FAIL %s: %v\n", f.Case.Name, f.Err)
+		} else {
+			fmt.Printf("FAIL %s: 得到 %q，期望 %q\n", f.Case.Name, f.Actual, f.Case.Expected)
+		}
+		if *verbose {
+			fmt.Printf("     %s %v\n", f.Case.Op, f.Case.Args)
+		}
+	}
+	fmt.Printf("%s：共 %d 个用例，通过 %d，失败 %d，跳过 %d\n",
+		target, report.Total, report.Passed, len(report.Failures), report.Skipped)
+	if !report.OK() {
+		os.Exit(1)
+	}
+}
+
+func loadVectors(path string) (*kat.Vectors, error) {
+	if path == "" {
+		return kat.Load()
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return kat.Parse(data)
+}
+
+func engineByName(name string) (saes.BlockCipher, error) {
+	if name == "" || name == "reference" {
+		return saes.Standard, nil
+	}
+	return saes.NewEngine(name, saes.StandardCipher())
+}
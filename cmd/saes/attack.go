@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"S-AES/utils"
+)
+
+// pairList 收集可重复的 --pair PLAIN:CIPHER 参数。
+type pairList []utils.PlainCipherPair
+
+func (p *pairList) String() string {
+	parts := make([]string, len(*p))
+	for i, pair := range *p {
+		parts[i] = utils.FormatHex16(pair.Plain) + ":" + utils.FormatHex16(pair.Cipher)
+	}
+	return strings.Join(parts, ",")
+}
+
+func (p *pairList) Set(value string) error {
+	plainText, cipherText, ok := strings.Cut(value, ":")
+	if !ok {
+		return fmt.Errorf("明密文对格式应为 PLAIN:CIPHER")
+	}
+	plain, err := utils.ParseBlockString(plainText)
+	if err != nil {
+		return fmt.Errorf("明文解析失败: %w", err)
+	}
+	cipher, err := utils.ParseBlockString(cipherText)
+	if err != nil {
+		return fmt.Errorf("密文解析失败: %w", err)
+	}
+	*p = append(*p, utils.PlainCipherPair{Plain: plain, Cipher: cipher})
+	return nil
+}
+
+// runAttack 对二重加密执行中间相遇攻击（mitm），或对单重加密穷举密钥（brute）。
+func runAttack(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("用法: saes attack <mitm|brute> --pair PLAIN:CIPHER [--pair ...]")
+	}
+	kind := args[0]
+	fs := flag.NewFlagSet("attack "+kind, flag.ExitOnError)
+	var pairs pairList
+	fs.Var(&pairs, "pair", "已知明密文对 PLAIN:CIPHER，可重复指定")
+	engine := fs.String("engine", "reference", engineUsage())
+	fs.Parse(args[1:])
+
+	if len(pairs) == 0 {
+		return fmt.Errorf("至少需要一个 --pair")
+	}
+	bc, err := engineByName(*engine)
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case "mitm":
+		keys, err := utils.MeetInTheMiddleAttack(bc, pairs)
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			fmt.Printf("K1=%s K2=%s key=%s\n", utils.FormatHex16(k.K1), utils.FormatHex16(k.K2), utils.FormatCombinedHex(k.K1, k.K2))
+		}
+		fmt.Printf("共 %d 个候选密钥\n", len(keys))
+	case "brute":
+		keys, err := utils.BruteForceAttack(bc, pairs)
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			fmt.Printf("key=%s\n", utils.FormatHex16(k))
+		}
+		fmt.Printf("共 %d 个候选密钥\n", len(keys))
+	default:
+		return fmt.Errorf("未知的攻击类型: %s（可选 mitm、brute）", kind)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"S-AES/utils"
+	"S-AES/utils/armor"
+	"S-AES/utils/saes"
+)
+
+// runCrypt 实现 encrypt 与 decrypt。未指定 --iv 时，加密生成随机 IV 并写在密文前 2 字节，解密从密文前 2 字节读取。
+func runCrypt(args []string, encrypt bool) error {
+	name := "decrypt"
+	if encrypt {
+		name = "encrypt"
+	}
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	key := fs.String("key", "", "密钥（16/32/48 位二进制或 0x 十六进制）")
+	modeName := fs.String("mode", "cbc", "工作模式：ecb、cbc、ctr、cfb、ofb")
+	paddingName := fs.String("padding", "pkcs7", "ECB/CBC 的填充方式：pkcs7、zero、none")
+	ivText := fs.String("iv", "", "初始向量（CTR 中为初始计数器）；留空时随机生成并置于密文之前")
+	in := fs.String("in", "-", "输入文件，- 表示标准输入")
+	out := fs.String("out", "-", "输出文件，- 表示标准输出")
+	encoding := fs.String("encoding", "base64", "密文的编码：raw、hex、base64、armor（armor 在头部记录模式与 IV，解密时自动识别）")
+	engine := fs.String("engine", "reference", engineUsage())
+	fs.Parse(args)
+
+	if *key == "" {
+		return fmt.Errorf("缺少 --key")
+	}
+	mode, err := saes.ParseMode(*modeName)
+	if err != nil {
+		return err
+	}
+	padding, err := saes.ParsePadding(*paddingName)
+	if err != nil {
+		return err
+	}
+	bc, err := engineByName(*engine)
+	if err != nil {
+		return err
+	}
+	kb, err := saes.NewKeyedBlock(bc, *key)
+	if err != nil {
+		return err
+	}
+
+	input, err := readInput(*in)
+	if err != nil {
+		return err
+	}
+
+	var output []byte
+	if strings.EqualFold(*encoding, "armor") {
+		output, err = cryptArmored(kb, mode, padding, *ivText, input, encrypt)
+	} else if encrypt {
+		output, err = encryptData(kb, mode, padding, *ivText, input)
+		if err == nil {
+			output, err = encodeCiphertext(output, *encoding)
+		}
+	} else {
+		var data []byte
+		data, err = decodeCiphertext(input, *encoding)
+		if err == nil {
+			output, err = decryptData(kb, mode, padding, *ivText, data)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return writeOutput(*out, output)
+}
+
+func encryptData(kb *saes.KeyedBlock, mode saes.Mode, padding saes.Padding, ivText string, plaintext []byte) ([]byte, error) {
+	if !mode.NeedsIV() {
+		return saes.EncryptBytes(kb, mode, padding, 0, plaintext)
+	}
+	if ivText != "" {
+		iv, err := utils.ParseBlockString(ivText)
+		if err != nil {
+			return nil, fmt.Errorf("无法解析初始向量: %w", err)
+		}
+		return saes.EncryptBytes(kb, mode, padding, iv, plaintext)
+	}
+
+	var prefix [2]byte
+	if _, err := rand.Read(prefix[:]); err != nil {
+		return nil, fmt.Errorf("生成初始向量失败: %w", err)
+	}
+	iv := uint16(prefix[0])<<8 | uint16(prefix[1])
+	ciphertext, err := saes.EncryptBytes(kb, mode, padding, iv, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return append(prefix[:], ciphertext...), nil
+}
+
+func decryptData(kb *saes.KeyedBlock, mode saes.Mode, padding saes.Padding, ivText string, ciphertext []byte) ([]byte, error) {
+	if !mode.NeedsIV() {
+		return saes.DecryptBytes(kb, mode, padding, 0, ciphertext)
+	}
+	if ivText != "" {
+		iv, err := utils.ParseBlockString(ivText)
+		if err != nil {
+			return nil, fmt.Errorf("无法解析初始向量: %w", err)
+		}
+		return saes.DecryptBytes(kb, mode, padding, iv, ciphertext)
+	}
+	if len(ciphertext) < 2 {
+		return nil, fmt.Errorf("密文过短，缺少前置的初始向量")
+	}
+	iv := uint16(ciphertext[0])<<8 | uint16(ciphertext[1])
+	return saes.DecryptBytes(kb, mode, padding, iv, ciphertext[2:])
+}
+
+// cryptArmored 处理 armor 编码：IV 写入封装头部而不是密文之前，解密参数全部取自头部。
+func cryptArmored(kb *saes.KeyedBlock, mode saes.Mode, padding saes.Padding, ivText string, input []byte, encrypt bool) ([]byte, error) {
+	if !encrypt {
+		plain, _, err := armor.Open(kb, string(input))
+		return plain, err
+	}
+	var iv uint16
+	if ivText != "" {
+		var err error
+		if iv, err = utils.ParseBlockString(ivText); err != nil {
+			return nil, fmt.Errorf("无法解析初始向量: %w", err)
+		}
+	} else if mode.NeedsIV() {
+		var b [2]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			return nil, fmt.Errorf("生成初始向量失败: %w", err)
+		}
+		iv = uint16(b[0])<<8 | uint16(b[1])
+	}
+	text, err := armor.Seal(kb, mode, padding, iv, input)
+	return []byte(text), err
+}
+
+func encodeCiphertext(data []byte, encoding string) ([]byte, error) {
+	switch strings.ToLower(encoding) {
+	case "raw":
+		return data, nil
+	case "hex":
+		return []byte(hex.EncodeToString(data) + "\n"), nil
+	case "base64":
+		return []byte(base64.StdEncoding.EncodeToString(data) + "\n"), nil
+	default:
+		return nil, fmt.Errorf("不支持的编码: %s（可选 raw、hex、base64）", encoding)
+	}
+}
+
+func decodeCiphertext(data []byte, encoding string) ([]byte, error) {
+	switch strings.ToLower(encoding) {
+	case "raw":
+		return data, nil
+	case "hex":
+		out, err := hex.DecodeString(strings.Join(strings.Fields(string(data)), ""))
+		if err != nil {
+			return nil, fmt.Errorf("十六进制解码失败: %w", err)
+		}
+		return out, nil
+	case "base64":
+		out, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(string(data)), ""))
+		if err != nil {
+			return nil, fmt.Errorf("Base64 解码失败: %w", err)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("不支持的编码: %s（可选 raw、hex、base64）", encoding)
+	}
+}
+
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+func writeOutput(path string, data []byte) error {
+	if path == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// runKeygen 生成可直接用于 --key 的随机密钥。
+func runKeygen(args []string) error {
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	bits := fs.Int("bits", 16, "密钥位数：16、32 或 48（后两者用于二重/三重加密）")
+	format := fs.String("format", "hex", "输出格式：hex 或 bin")
+	count := fs.Int("count", 1, "生成的密钥个数")
+	fs.Parse(args)
+
+	if *bits != 16 && *bits != 32 && *bits != 48 {
+		return fmt.Errorf("密钥位数必须为 16、32 或 48")
+	}
+	if *format != "hex" && *format != "bin" {
+		return fmt.Errorf("不支持的格式: %s（可选 hex、bin）", *format)
+	}
+	buf := make([]byte, *bits/8)
+	for i := 0; i < *count; i++ {
+		if _, err := rand.Read(buf); err != nil {
+			return fmt.Errorf("生成随机密钥失败: %w", err)
+		}
+		if *format == "hex" {
+			fmt.Println("0x" + strings.ToUpper(hex.EncodeToString(buf)))
+			continue
+		}
+		var sb strings.Builder
+		for _, b := range buf {
+			fmt.Fprintf(&sb, "%08b", b)
+		}
+		fmt.Println(sb.String())
+	}
+	return nil
+}
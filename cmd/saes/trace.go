@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"S-AES/utils"
+	"S-AES/utils/saes"
+)
+
+// runTrace 输出标准 S-AES 加密一个分组时的轮密钥与每一步之后的状态。
+func runTrace(args []string) error {
+	fs := flag.NewFlagSet("trace", flag.ExitOnError)
+	keyText := fs.String("key", "", "16 位密钥（二进制或 0x 十六进制）")
+	blockText := fs.String("block", "", "16 位明文分组（二进制或 0x 十六进制）")
+	fs.Parse(args)
+
+	if *keyText == "" || *blockText == "" {
+		return fmt.Errorf("需要 --key 与 --block")
+	}
+	key, err := utils.ParseBlockString(*keyText)
+	if err != nil {
+		return fmt.Errorf("无法解析密钥: %w", err)
+	}
+	block, err := utils.ParseBlockString(*blockText)
+	if err != nil {
+		return fmt.Errorf("无法解析分组: %w", err)
+	}
+
+	c := saes.StandardCipher()
+	for i, rk := range c.RoundKeys(key) {
+		fmt.Printf("K%-14d %s  %s\n", i, utils.FormatHex16(rk), utils.FormatBinary16(rk))
+	}
+	fmt.Printf("%-15s %s  %s\n", "plaintext", utils.FormatHex16(block), utils.FormatBinary16(block))
+	out := c.EncryptBlockProbed(block, key, func(p saes.Point, state uint16) uint16 {
+		fmt.Printf("%-15s %s  %s\n", fmt.Sprintf("R%d %s", p.Round, p.Step), utils.FormatHex16(state), utils.FormatBinary16(state))
+		return state
+	})
+	fmt.Printf("%-15s %s  %s\n", "ciphertext", utils.FormatHex16(out), utils.FormatBinary16(out))
+	return nil
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"S-AES/models"
+	"S-AES/utils"
+	"S-AES/utils/algebra"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultSolveLimit 与 maxSolveLimit 限制代数攻击枚举的密钥个数。
+	defaultSolveLimit = 16
+	maxSolveLimit     = 256
+	// defaultMaxConflicts 为 max_conflicts 的默认值与上限，防止求解器长时间占用请求。
+	defaultMaxConflicts = 1000000
+	// maxAlgebraPairs 限制明密文对的个数，每一对都会为方程组增加一整份加密电路。
+	maxAlgebraPairs = 16
+)
+
+// ExportAlgebra 把明密文对对应的方程组以 ANF、DIMACS CNF 或 CryptoMiniSat XOR 格式下载。
+func ExportAlgebra(c *gin.Context) {
+	var req models.AlgebraExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	sys, ok := buildAlgebraSystem(c, req.Pairs, req.InputEncoding, req.Cipher)
+	if !ok {
+		return
+	}
+
+	var (
+		filename string
+		write    func() error
+	)
+	switch strings.ToLower(strings.TrimSpace(req.Format)) {
+	case "", "cnf", "dimacs":
+		filename = "saes.cnf"
+		write = func() error { return algebra.WriteDIMACS(c.Writer, sys) }
+	case "xcnf", "xor":
+		filename = "saes.xcnf"
+		write = func() error { return algebra.WriteXCNF(c.Writer, sys) }
+	case "anf":
+		filename = "saes.anf"
+		write = func() error { return algebra.WriteANF(c.Writer, sys) }
+	default:
+		respondError(c, http.StatusBadRequest, 1, "不支持的格式（可选 anf、cnf、xcnf）")
+		return
+	}
+
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+	if err := write(); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+// SolveAlgebra 用内置 CDCL 求解器恢复与全部明密文对一致的密钥，max_conflicts 为整个枚举过程共用的冲突预算。
+func SolveAlgebra(c *gin.Context) {
+	var req models.AlgebraSolveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	cipher, err := buildGenericCipher(req.Cipher)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	pairs, err := parseAlgebraPairs(req.Pairs, req.InputEncoding)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSolveLimit
+	}
+	if limit > maxSolveLimit {
+		limit = maxSolveLimit
+	}
+	opts := algebra.Options{MaxConflicts: req.MaxConflicts}
+	if opts.MaxConflicts <= 0 || opts.MaxConflicts > defaultMaxConflicts {
+		opts.MaxConflicts = defaultMaxConflicts
+	}
+
+	start := time.Now()
+	result, err := algebra.RecoverKeys(cipher, pairs, limit, opts)
+	if err != nil {
+		respondError(c, http.StatusUnprocessableEntity, 1, err.Error())
+		return
+	}
+
+	keys := make([]models.BruteForceKey, 0, len(result.Keys))
+	for _, key := range result.Keys {
+		keys = append(keys, models.BruteForceKey{
+			Hex: utils.FormatHex16(key),
+			Bin: utils.FormatBinary16(key),
+		})
+	}
+	respondSuccess(c, models.AlgebraSolveResponse{
+		Variables: result.Vars,
+		Clauses:   result.Clauses,
+		Count:     len(keys),
+		Keys:      keys,
+		Complete:  result.Complete,
+		ElapsedMs: time.Since(start).Milliseconds(),
+		Stats:     models.AlgebraStats(result.Stats),
+	})
+}
+
+func buildAlgebraSystem(c *gin.Context, input []models.AttackPair, encoding string, opts *models.CipherOptions) (*algebra.System, bool) {
+	cipher, err := buildGenericCipher(opts)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return nil, false
+	}
+	pairs, err := parseAlgebraPairs(input, encoding)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return nil, false
+	}
+	sys, err := algebra.Build(cipher, pairs)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return nil, false
+	}
+	return sys, true
+}
+
+func parseAlgebraPairs(input []models.AttackPair, encoding string) ([]algebra.Pair, error) {
+	if len(input) > maxAlgebraPairs {
+		return nil, fmt.Errorf("明密文对至多 %d 组", maxAlgebraPairs)
+	}
+	parsed, err := parseAttackPairs(input, encoding)
+	if err != nil {
+		return nil, err
+	}
+	pairs := make([]algebra.Pair, 0, len(parsed))
+	for _, p := range parsed {
+		pairs = append(pairs, algebra.Pair{Plaintext: p.Plain, Ciphertext: p.Cipher})
+	}
+	return pairs, nil
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"S-AES/models"
+	"S-AES/utils"
+	"S-AES/utils/dudect"
+	"S-AES/utils/saes"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxTimingMeasurements = 2000000
+	// maxTimingRepeat 限制每次采样内的重复次数，单个请求的总耗时与 Measurements×Repeat 成正比。
+	maxTimingRepeat = 64
+)
+
+// TimingAnalysis 分别对查表/分支实现与常量时间实现运行 dudect 检测。
+func TimingAnalysis(c *gin.Context) {
+	var req models.TimingAnalysisRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+
+	cfg := dudect.DefaultConfig()
+	if req.Measurements > 0 {
+		cfg.Measurements = min(req.Measurements, maxTimingMeasurements)
+	}
+	if req.Repeat > maxTimingRepeat {
+		respondError(c, http.StatusBadRequest, 1, fmt.Sprintf("repeat 不能超过 %d", maxTimingRepeat))
+		return
+	}
+	if req.Repeat > 0 {
+		cfg.Repeat = req.Repeat
+	}
+	if req.Seed != 0 {
+		cfg.Seed = req.Seed
+	}
+	if strings.TrimSpace(req.Key) != "" {
+		key, err := utils.ParseBlockString(req.Key)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, 1, "无法解析密钥: "+err.Error())
+			return
+		}
+		cfg.Key = key
+	}
+	if strings.TrimSpace(req.FixedPlaintext) != "" {
+		block, err := utils.ParseBlockString(req.FixedPlaintext)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, 1, "无法解析固定明文: "+err.Error())
+			return
+		}
+		cfg.FixedBlock = block
+	}
+
+	leaky, hardened := saes.Standard, saes.ConstantTime
+	if req.Cipher != nil {
+		params, err := parseCipherParams(req.Cipher)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, 1, err.Error())
+			return
+		}
+		cipher, err := saes.NewCipher(params)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, 1, err.Error())
+			return
+		}
+		leaky, hardened = cipher, saes.NewConstantTimeCipher(cipher)
+	}
+
+	resp := models.TimingAnalysisResponse{Threshold: dudect.Threshold}
+	for _, target := range []struct {
+		name string
+		bc   saes.BlockCipher
+	}{
+		{"reference", leaky},
+		{"constant-time", hardened},
+	} {
+		result, err := dudect.Run(target.bc.EncryptBlock, cfg)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, 1, err.Error())
+			return
+		}
+		crops := make([]models.TimingCrop, 0, len(result.Crops))
+		for _, crop := range result.Crops {
+			crops = append(crops, models.TimingCrop{Percentile: crop.Percentile, T: crop.T})
+		}
+		resp.Results = append(resp.Results, models.TimingResult{
+			Engine:       target.name,
+			Measurements: result.Measurements,
+			FixedCount:   result.FixedCount,
+			RandomCount:  result.RandomCount,
+			FixedMeanNs:  result.FixedMean,
+			RandomMeanNs: result.RandomMean,
+			T:            result.T,
+			Crops:        crops,
+			MaxAbsT:      result.MaxAbsT,
+			Leaky:        result.Leaky,
+		})
+	}
+
+	respondSuccess(c, resp)
+}
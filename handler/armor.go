@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"S-AES/utils/armor"
+	"S-AES/utils/saes"
+)
+
+// armorCiphertext 把已加密的数据连同模式、填充、IV、密钥长度与可选的 Key-ID 封装为 ASCII 文本。
+func armorCiphertext(kb *saes.KeyedBlock, mode saes.Mode, padding saes.Padding, iv *uint16, keyID string, data []byte) string {
+	return armor.Encode(&armor.Message{
+		Mode:    mode,
+		Padding: padding,
+		IV:      iv,
+		KeyBits: kb.KeyBits(),
+		KeyID:   keyID,
+		Data:    data,
+	})
+}
+
+// armoredInput 返回请求中的 ASCII 封装文本（JSON 字段或请求体），不是封装格式时返回空串。
+func armoredInput(raw []byte, text string) string {
+	if raw != nil {
+		text = string(raw)
+	}
+	if armor.IsArmored(text) {
+		return text
+	}
+	return ""
+}
+
+// openArmored 按封装头部记录的参数解密，忽略请求中的 IV 等参数。
+func openArmored(kb *saes.KeyedBlock, text string) ([]byte, error) {
+	plain, _, err := armor.Open(kb, text)
+	return plain, err
+}
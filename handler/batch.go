@@ -0,0 +1,316 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"S-AES/models"
+	"S-AES/utils"
+	"S-AES/utils/codec"
+	"S-AES/utils/saes"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxBatchOperations 限制单次批量请求的操作数。
+	maxBatchOperations = 10000
+	// maxBatchConcurrency 限制批量请求的并发上限。
+	maxBatchConcurrency = 64
+	// maxBatchPasswords 限制单次批量请求中不同口令参数组合的个数，每种组合只派生一次。
+	maxBatchPasswords = 8
+)
+
+// batchEnv 为全部操作共用的分组密码与预先派生的口令密钥；generic 供 trace 插桩，仅在需要时构造。
+type batchEnv struct {
+	bc        saes.BlockCipher
+	generic   *saes.Cipher
+	passwords map[models.PasswordKey]derivedKey
+}
+
+// derivedKey 为一组口令参数的派生结果。
+type derivedKey struct {
+	key string
+	err error
+}
+
+// Batch 以有界的工作池并发执行多个加密、解密或追踪操作，逐项返回结果或错误。
+// 单项失败不影响其余操作，响应始终按请求中的顺序排列。
+func Batch(c *gin.Context) {
+	var req models.BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	if n := len(req.Operations); n == 0 || n > maxBatchOperations {
+		respondError(c, http.StatusBadRequest, 1, fmt.Sprintf("operations 必须包含 1 到 %d 个操作", maxBatchOperations))
+		return
+	}
+	workers := req.Concurrency
+	if workers == 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers < 1 || workers > maxBatchConcurrency {
+		respondError(c, http.StatusBadRequest, 1, fmt.Sprintf("concurrency 必须在 1 到 %d 之间", maxBatchConcurrency))
+		return
+	}
+	workers = min(workers, len(req.Operations))
+
+	var (
+		env batchEnv
+		err error
+	)
+	if env.bc, err = buildCipher(req.Cipher); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	for _, op := range req.Operations {
+		if strings.EqualFold(strings.TrimSpace(op.Op), "trace") {
+			if env.generic, err = buildGenericCipher(req.Cipher); err != nil {
+				respondError(c, http.StatusBadRequest, 1, err.Error())
+				return
+			}
+			break
+		}
+	}
+	if env.passwords, err = deriveBatchPasswords(req.Operations); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+
+	start := time.Now()
+	results := make([]models.BatchResult, len(req.Operations))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = runBatchOperation(env, i, req.Operations[i])
+			}
+		}()
+	}
+	done := c.Request.Context().Done()
+	next := 0
+feed:
+	for ; next < len(req.Operations); next++ {
+		select {
+		case jobs <- next:
+		case <-done:
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	for i := next; i < len(req.Operations); i++ {
+		results[i] = models.BatchResult{Index: i, ID: req.Operations[i].ID, Error: "请求已取消"}
+	}
+
+	resp := models.BatchResponse{Total: len(results), Results: results, ElapsedMs: durationMs(time.Since(start))}
+	for _, r := range results {
+		if r.OK {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+	}
+	respondSuccess(c, resp)
+}
+
+// usesPassword 报告操作是否只以口令给出密钥；口令与 key 或 key_id 同时出现时由 requestKey 报错，无需派生。
+func usesPassword(op models.BatchOperation) bool {
+	return op.PasswordKey.Password != "" && strings.TrimSpace(op.Key) == "" && strings.TrimSpace(op.KeyID) == ""
+}
+
+// deriveBatchPasswords 在分发之前为每种不同的口令参数派生一次密钥，派生失败记入结果，由对应操作各自报错。
+func deriveBatchPasswords(ops []models.BatchOperation) (map[models.PasswordKey]derivedKey, error) {
+	passwords := make(map[models.PasswordKey]derivedKey)
+	for _, op := range ops {
+		if usesPassword(op) {
+			passwords[op.PasswordKey] = derivedKey{}
+		}
+	}
+	if len(passwords) > maxBatchPasswords {
+		return nil, fmt.Errorf("单次批量请求至多使用 %d 种不同的口令参数", maxBatchPasswords)
+	}
+	for pk := range passwords {
+		key, err := resolveKey("", pk)
+		passwords[pk] = derivedKey{key: key, err: err}
+	}
+	return passwords, nil
+}
+
+// runBatchOperation 执行单个操作。工作协程不在 gin.Recovery 的保护范围内，panic 在此转为该项的错误。
+func runBatchOperation(env batchEnv, index int, op models.BatchOperation) (result models.BatchResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = models.BatchResult{Index: index, ID: op.ID, Error: fmt.Sprintf("内部错误: %v", r)}
+		}
+	}()
+
+	result = models.BatchResult{Index: index, ID: op.ID}
+	var err error
+	if usesPassword(op) {
+		d := env.passwords[op.PasswordKey]
+		if d.err != nil {
+			return models.BatchResult{Index: index, ID: op.ID, Error: d.err.Error()}
+		}
+		op.Key, op.PasswordKey = d.key, models.PasswordKey{}
+	}
+	switch strings.ToLower(strings.TrimSpace(op.Op)) {
+	case "encrypt":
+		err = batchEncrypt(env.bc, op, &result)
+	case "decrypt":
+		err = batchDecrypt(env.bc, op, &result)
+	case "trace":
+		err = batchTrace(env.generic, op, &result)
+	default:
+		err = fmt.Errorf("不支持的操作: %q（可选 encrypt、decrypt、trace）", op.Op)
+	}
+	if err != nil {
+		return models.BatchResult{Index: index, ID: op.ID, Error: err.Error()}
+	}
+	result.OK = true
+	return result
+}
+
+// batchModeOptions 解析单个操作的模式、填充与输出编码；批量接口的结果嵌在 JSON 中，不支持 raw。
+func batchModeOptions(op models.BatchOperation) (saes.Mode, saes.Padding, codec.Encoding, error) {
+	mode := saes.ModeECB
+	if strings.TrimSpace(op.Mode) != "" {
+		var err error
+		if mode, err = saes.ParseMode(op.Mode); err != nil {
+			return "", "", "", err
+		}
+	}
+	padding := saes.PaddingNone
+	if strings.TrimSpace(op.Padding) != "" {
+		var err error
+		if padding, err = saes.ParsePadding(op.Padding); err != nil {
+			return "", "", "", err
+		}
+	}
+	outEnc, err := outputEncoding(op.OutputEncoding, blockEncoding, false)
+	if err != nil {
+		return "", "", "", err
+	}
+	if outEnc == codec.Raw {
+		return "", "", "", errors.New("批量接口不支持 raw 编码")
+	}
+	return mode, padding, outEnc, nil
+}
+
+func batchEncrypt(bc saes.BlockCipher, op models.BatchOperation, result *models.BatchResult) error {
+	mode, padding, outEnc, err := batchModeOptions(op)
+	if err != nil {
+		return err
+	}
+	kb, stored, err := requestKey(bc, op.Key, op.KeyID, op.PasswordKey, 0)
+	if err != nil {
+		return err
+	}
+	plain, err := decodeInput(nil, op.Data, op.InputEncoding, blockEncoding, "数据")
+	if err != nil {
+		return err
+	}
+	var iv uint16
+	if mode.NeedsIV() {
+		if iv, err = requestIV(op.IV); err != nil {
+			return err
+		}
+		result.IV = fmt.Sprintf("0x%04X", iv)
+	}
+	cipher, err := saes.EncryptBytes(kb, mode, padding, iv, plain)
+	if err != nil {
+		return err
+	}
+	out, err := encodeOutput(outEnc, cipher)
+	if err != nil {
+		return err
+	}
+	result.Output = storedKeyPrefix(stored, gin.H{}) + out
+	if stored != nil {
+		result.KeyVersion = stored.Version
+	}
+	return nil
+}
+
+func batchDecrypt(bc saes.BlockCipher, op models.BatchOperation, result *models.BatchResult) error {
+	mode, padding, outEnc, err := batchModeOptions(op)
+	if err != nil {
+		return err
+	}
+	in, err := resolveCipherInput(bc, nil, op.Data, op.Key, op.KeyID, op.PasswordKey)
+	if err != nil {
+		return err
+	}
+	var plain []byte
+	if in.armored != "" {
+		if plain, err = openArmored(in.kb, in.armored); err != nil {
+			return err
+		}
+	} else {
+		var iv uint16
+		if mode.NeedsIV() {
+			if strings.TrimSpace(op.IV) == "" {
+				return fmt.Errorf("%s 模式解密需要 iv", mode)
+			}
+			if iv, err = utils.ParseBlockString(op.IV); err != nil {
+				return fmt.Errorf("无法解析初始向量: %v", err)
+			}
+		}
+		cipher, err := decodeInput(nil, in.text, op.InputEncoding, blockEncoding, "数据")
+		if err != nil {
+			return err
+		}
+		if plain, err = saes.DecryptBytes(in.kb, mode, padding, iv, cipher); err != nil {
+			return err
+		}
+	}
+	result.Output, err = encodeOutput(outEnc, plain)
+	return err
+}
+
+// batchTrace 记录用 16 位密钥加密单个分组时的轮密钥与每一步之后的状态。
+func batchTrace(c *saes.Cipher, op models.BatchOperation, result *models.BatchResult) error {
+	if strings.TrimSpace(op.KeyID) != "" || op.Password != "" {
+		return errors.New("trace 只接受 key")
+	}
+	keys, err := saes.ParseKey(op.Key)
+	if err != nil {
+		return fmt.Errorf("无法解析密钥: %w", err)
+	}
+	if len(keys) != 1 {
+		return errors.New("trace 只支持 16 位密钥")
+	}
+	_, _, outEnc, err := batchModeOptions(op)
+	if err != nil {
+		return err
+	}
+	data, err := decodeInput(nil, op.Data, op.InputEncoding, blockEncoding, "数据")
+	if err != nil {
+		return err
+	}
+	if len(data) != 2 {
+		return errors.New("trace 只接受单个 16 位分组")
+	}
+
+	block := uint16(data[0])<<8 | uint16(data[1])
+	trace := &models.BlockTrace{}
+	for _, rk := range c.RoundKeys(keys[0]) {
+		trace.RoundKeys = append(trace.RoundKeys, utils.FormatHex16(rk))
+	}
+	out := c.EncryptBlockProbed(block, keys[0], func(p saes.Point, state uint16) uint16 {
+		trace.Steps = append(trace.Steps, models.TraceStep{Round: p.Round, Step: p.Step.String(), State: utils.FormatHex16(state)})
+		return state
+	})
+	result.Trace = trace
+	result.Output, err = encodeOutput(outEnc, []byte{byte(out >> 8), byte(out)})
+	return err
+}
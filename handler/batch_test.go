@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"S-AES/utils/saes"
+)
+
+func TestBatchMixedResults(t *testing.T) {
+	body := `{"concurrency":4,"operations":[
+		{"id":"a","op":"encrypt","key":"0x2D55","data":"1010011101001001"},
+		{"id":2,"op":"shuffle","key":"0x2D55","data":"1010011101001001"},
+		{"id":"c","op":"encrypt","password":"hunter2","salt":"s","input_encoding":"hex","output_encoding":"hex","data":"a749"},
+		{"id":"d","op":"encrypt","password":"hunter2","salt":"s","kdf":"md5","data":"1010011101001001"},
+		{"id":"e","op":"encrypt","password":"hunter2","salt":"s","input_encoding":"hex","output_encoding":"hex","data":"a749"},
+		{"id":"f","op":"trace","key":"0x2D55","data":"1010011101001001"},
+		{"id":"g","op":"decrypt","key":"0x2D55","data":"0110"}
+	]}`
+	code, resp := postJSON(t, Batch, body)
+	if code != http.StatusOK {
+		t.Fatalf("状态码 %d: %v", code, resp)
+	}
+	data := resp["data"].(map[string]any)
+	results := data["results"].([]any)
+	if data["total"] != float64(7) || data["succeeded"] != float64(4) || data["failed"] != float64(3) {
+		t.Fatalf("统计不符: %v", data)
+	}
+
+	wantIDs := []any{"a", float64(2), "c", "d", "e", "f", "g"}
+	wantOK := []bool{true, false, true, false, true, true, false}
+	for i, r := range results {
+		item := r.(map[string]any)
+		if item["index"] != float64(i) || item["id"] != wantIDs[i] {
+			t.Fatalf("第 %d 项的顺序或 id 不符: %v", i, item)
+		}
+		if ok, _ := item["ok"].(bool); ok != wantOK[i] {
+			t.Fatalf("第 %d 项 ok=%v，期望 %v: %v", i, ok, wantOK[i], item)
+		}
+		if !wantOK[i] && item["error"] == "" {
+			t.Fatalf("第 %d 项失败但没有错误信息", i)
+		}
+	}
+	first := results[0].(map[string]any)
+	if want := fmt.Sprintf("%016b", saes.EncryptBlockRaw(0xA749, 0x2D55)); first["output"] != want {
+		t.Fatalf("加密结果为 %v，期望 %s", first["output"], want)
+	}
+	c, e := results[2].(map[string]any), results[4].(map[string]any)
+	if c["output"] != e["output"] {
+		t.Fatalf("同一口令的两次加密结果不同: %v %v", c["output"], e["output"])
+	}
+	if trace, _ := results[5].(map[string]any)["trace"].(map[string]any); len(trace["round_keys"].([]any)) != 3 {
+		t.Fatalf("trace 应返回 3 个轮密钥: %v", trace)
+	}
+}
+
+func TestBatchRejectsTooManyPasswords(t *testing.T) {
+	ops := make([]string, maxBatchPasswords+1)
+	for i := range ops {
+		ops[i] = fmt.Sprintf(`{"op":"encrypt","password":"p%d","data":"0000000000000000"}`, i)
+	}
+	code, resp := postJSON(t, Batch, `{"operations":[`+strings.Join(ops, ",")+`]}`)
+	if code != http.StatusBadRequest {
+		t.Fatalf("超过口令上限应返回 400，得到 %d %v", code, resp)
+	}
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"S-AES/models"
+	"S-AES/utils/saes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// buildCipher 根据请求中的可选参数构造分组实现，未提供时使用标准 S-AES。
+func buildCipher(opts *models.CipherOptions) (saes.BlockCipher, error) {
+	if opts == nil {
+		return saes.Standard, nil
+	}
+	params, err := parseCipherParams(opts)
+	if err != nil {
+		return nil, err
+	}
+	cipher, err := saes.NewCipher(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return saes.NewEngine(opts.Engine, cipher)
+}
+
+// engineOptions 把查询参数中的 engine 转为 CipherOptions：这类接口只能选择标准 S-AES 的实现，为空时返回 nil。
+func engineOptions(engine string) *models.CipherOptions {
+	if strings.TrimSpace(engine) == "" {
+		return nil
+	}
+	return &models.CipherOptions{Engine: engine}
+}
+
+// buildGenericCipher 与 buildCipher 相同，但总是返回可插桩的通用实现，忽略 engine。
+func buildGenericCipher(opts *models.CipherOptions) (*saes.Cipher, error) {
+	if opts == nil {
+		return saes.StandardCipher(), nil
+	}
+	params, err := parseCipherParams(opts)
+	if err != nil {
+		return nil, err
+	}
+	return saes.NewCipher(params)
+}
+
+func parseCipherParams(opts *models.CipherOptions) (saes.Params, error) {
+	params := saes.DefaultParams()
+	if opts.Rounds != 0 {
+		params.Rounds = opts.Rounds
+	}
+	params.FinalMixColumns = opts.FinalMixColumns
+	if strings.TrimSpace(opts.SBox) != "" {
+		box, err := saes.ParseSBox(opts.SBox)
+		if err != nil {
+			return params, err
+		}
+		params.SBox = box
+	}
+	if strings.TrimSpace(opts.Poly) != "" {
+		p, err := saes.ParsePoly(opts.Poly)
+		if err != nil {
+			return params, err
+		}
+		params.Poly = p
+	}
+	if strings.TrimSpace(opts.MixColumns) != "" {
+		m, err := saes.ParseMixColumns(opts.MixColumns)
+		if err != nil {
+			return params, err
+		}
+		params.MixColumns = m
+	}
+	return params, nil
+}
+
+func AnalyzeSBox(c *gin.Context) {
+	var req models.SBoxAnalysisRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+
+	params, err := parseCipherParams(&models.CipherOptions{
+		SBox:       req.SBox,
+		Poly:       req.Poly,
+		MixColumns: req.MixColumns,
+	})
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+
+	report := saes.AnalyzeSBox(params.SBox)
+	resp := models.SBoxAnalysisResponse{
+		SBox:                   formatNibbles(report.SBox[:]),
+		Bijective:              report.Bijective,
+		DifferentialUniformity: report.DifferentialUniformity,
+		DDT:                    report.DDT,
+		Nonlinearity:           report.Nonlinearity,
+		LAT:                    report.LAT,
+		AlgebraicDegree:        report.AlgebraicDegree,
+		CoordinateANF:          report.CoordinateANF,
+		FixedPoints:            report.FixedPoints,
+		Poly:                   saes.FormatPoly(params.Poly),
+		PolyIrreducible:        saes.IsIrreducible(params.Poly),
+		MixColumns:             formatNibbles(params.MixColumns[:]),
+	}
+	if resp.FixedPoints == nil {
+		resp.FixedPoints = []int{}
+	}
+	if report.Bijective {
+		resp.InverseSBox = formatNibbles(report.Inverse[:])
+	}
+	if branch, err := saes.MixColumnsBranchNumber(params.MixColumns, params.Poly); err == nil {
+		resp.BranchNumber = branch
+	}
+	if _, err := saes.NewCipher(params); err != nil {
+		resp.CipherError = err.Error()
+	} else {
+		resp.UsableAsCipher = true
+	}
+
+	respondSuccess(c, resp)
+}
+
+func formatNibbles(values []byte) string {
+	var sb strings.Builder
+	for _, v := range values {
+		sb.WriteByte("0123456789ABCDEF"[v&0x0F])
+	}
+	return sb.String()
+}
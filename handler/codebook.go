@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"S-AES/models"
+	"S-AES/utils/codebook"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportCodebook 穷举密钥下全部 65536 个分组的对照表。format 为 json 时只返回不动点与轮换结构，
+// 为 csv、jsonl 或 bin 时以附件输出整张表，并把摘要、不动点数与轮换数写入响应头。
+func ExportCodebook(c *gin.Context) {
+	var req models.CodebookRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	format := strings.ToLower(strings.TrimSpace(req.Format))
+	var export codebook.Format
+	if format != "" && format != "json" {
+		var err error
+		if export, err = codebook.ParseFormat(format); err != nil {
+			respondError(c, http.StatusBadRequest, 1, "不支持的格式（可选 json、csv、jsonl、bin）")
+			return
+		}
+	}
+	bc, err := buildCipher(engineOptions(req.Engine))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	kb, stored, err := requestKey(bc, req.Key, req.KeyID, req.PasswordKey, 0)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+
+	cb := codebook.New(kb)
+	stats := cb.Stats()
+	if export == "" {
+		out := gin.H{"size": codebook.Size, "key_bits": kb.KeyBits()}
+		storedKeyPrefix(stored, out)
+		out["stats"] = stats
+		respondSuccess(c, out)
+		return
+	}
+
+	contentType := map[codebook.Format]string{
+		codebook.FormatCSV:    "text/csv",
+		codebook.FormatJSONL:  "application/x-ndjson",
+		codebook.FormatBinary: octetStream,
+	}[export]
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="codebook.%s"`, export))
+	c.Header("X-SAES-Codebook-SHA256", stats.SHA256)
+	c.Header("X-SAES-Fixed-Points", strconv.Itoa(len(stats.FixedPoints)))
+	c.Header("X-SAES-Cycles", strconv.Itoa(stats.Cycles))
+	c.Status(http.StatusOK)
+	if err := cb.Write(c.Writer, export); err != nil {
+		_ = c.Error(err)
+	}
+}
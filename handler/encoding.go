@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"S-AES/utils/codec"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// blockEncoding 表示 /encrypt 与 /decrypt 原有的分组格式：输入为位串或 0x 十六进制，
+	// 输出为每个分组一个 16 位二进制串、分组之间以空格分隔。
+	blockEncoding codec.Encoding = ""
+	// maxRawBody 为 application/octet-stream 请求体的上限。
+	maxRawBody  = 16 << 20
+	octetStream = "application/octet-stream"
+)
+
+// bindCryptRequest 绑定加解密请求。application/octet-stream 请求的参数取自查询串，
+// 请求体原样作为输入返回（非 nil）；其余请求按 JSON 绑定，返回的 raw 为 nil。
+func bindCryptRequest(c *gin.Context, req interface{}) ([]byte, bool) {
+	if c.ContentType() != octetStream {
+		if err := c.ShouldBindJSON(req); err != nil {
+			respondError(c, http.StatusBadRequest, 1, err.Error())
+			return nil, false
+		}
+		return nil, true
+	}
+
+	if err := c.ShouldBindQuery(req); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return nil, false
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxRawBody+1))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, fmt.Sprintf("读取请求体失败: %v", err))
+		return nil, false
+	}
+	if len(body) > maxRawBody {
+		respondError(c, http.StatusRequestEntityTooLarge, 1, fmt.Sprintf("请求体超过 %d 字节上限", maxRawBody))
+		return nil, false
+	}
+	if body == nil {
+		body = []byte{}
+	}
+	return body, true
+}
+
+// decodeInput 按 input_encoding 还原输入。raw 非 nil 时输入取自请求体，默认按原始字节处理；
+// 否则解码 JSON 字段 text，name 为空时使用接口的默认编码 def。
+func decodeInput(raw []byte, text, name string, def codec.Encoding, field string) ([]byte, error) {
+	if raw != nil {
+		enc, err := codec.Parse(name, codec.Raw)
+		if err != nil {
+			return nil, err
+		}
+		if enc != codec.Raw {
+			return decodeText(enc, string(raw), field)
+		}
+		if len(raw) == 0 {
+			return nil, fmt.Errorf("%s不能为空", field)
+		}
+		return raw, nil
+	}
+
+	enc, err := codec.Parse(name, def)
+	if err != nil {
+		return nil, err
+	}
+	if enc == codec.Raw {
+		return nil, fmt.Errorf("raw 编码仅适用于 %s 请求", octetStream)
+	}
+	return decodeText(enc, text, field)
+}
+
+func decodeText(enc codec.Encoding, text, field string) ([]byte, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("%s不能为空", field)
+	}
+	var (
+		data []byte
+		err  error
+	)
+	if enc == blockEncoding {
+		data, err = codec.DecodeBlocks(text)
+	} else {
+		data, err = codec.Decode(enc, text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("无法解析%s: %w", field, err)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%s不能为空", field)
+	}
+	return data, nil
+}
+
+// outputEncoding 解析 output_encoding，并拒绝无法与 ASCII 封装同时使用的 raw 输出。
+func outputEncoding(name string, def codec.Encoding, armored bool) (codec.Encoding, error) {
+	enc, err := codec.Parse(name, def)
+	if err != nil {
+		return "", err
+	}
+	if enc == codec.Raw && armored {
+		return "", fmt.Errorf("armor 不能与 raw 输出编码同时使用")
+	}
+	return enc, nil
+}
+
+// respondCrypt 按编码 enc 输出结果，prefix（密钥版本前缀）置于编码结果之前。
+// raw 编码直接返回字节，IV 写入 X-SAES-IV 响应头；其余编码返回 JSON，extra 中的字段（如 iv、armored）一并附上。
+func respondCrypt(c *gin.Context, field, prefix string, data []byte, enc codec.Encoding, extra gin.H) {
+	if enc == codec.Raw {
+		if iv, ok := extra["iv"].(string); ok {
+			c.Header("X-SAES-IV", iv)
+		}
+		c.Data(http.StatusOK, octetStream, append([]byte(prefix), data...))
+		return
+	}
+
+	out, err := encodeOutput(enc, data)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+
+	resp := gin.H{field: prefix + out}
+	for k, v := range extra {
+		resp[k] = v
+	}
+	respondSuccess(c, resp)
+}
+
+// encodeOutput 把结果编码为文本，blockEncoding 输出以空格分隔的 16 位二进制分组。
+func encodeOutput(enc codec.Encoding, data []byte) (string, error) {
+	if enc == blockEncoding {
+		return formatBlocks(data)
+	}
+	return codec.Encode(enc, data)
+}
+
+func formatBlocks(data []byte) (string, error) {
+	if len(data)%2 != 0 {
+		return "", fmt.Errorf("结果长度不是 16 位分组的整数倍，请指定 output_encoding")
+	}
+	blocks := make([]string, 0, len(data)/2)
+	for i := 0; i < len(data); i += 2 {
+		blocks = append(blocks, fmt.Sprintf("%016b", uint16(data[i])<<8|uint16(data[i+1])))
+	}
+	return strings.Join(blocks, " "), nil
+}
+
+// parseByteString 解析字节串参数：0x 开头按十六进制解码，否则取 UTF-8 字节。
+func parseByteString(s string) ([]byte, error) {
+	trimmed := strings.TrimSpace(s)
+	if strings.HasPrefix(strings.ToLower(trimmed), "0x") {
+		b, err := hex.DecodeString(trimmed[2:])
+		if err != nil {
+			return nil, fmt.Errorf("无法解析十六进制字节串: %w", err)
+		}
+		return b, nil
+	}
+	return []byte(s), nil
+}
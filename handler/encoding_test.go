@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestRawBodyCipherOptions(t *testing.T) {
+	_, jsonResp := postJSON(t, Encrypt, `{"plaintext":"0xA749","key":"0x2D55","output_encoding":"hex","cipher":{"rounds":1,"engine":"bitslice"}}`)
+	code, rawResp := postRaw(t, Encrypt, "key=0x2D55&output_encoding=hex&rounds=1&engine=bitslice", []byte{0xA7, 0x49})
+	if code != http.StatusOK || !reflect.DeepEqual(rawResp["data"], jsonResp["data"]) {
+		t.Fatalf("查询参数中的 cipher 选项未生效: %d %v，JSON 请求为 %v", code, rawResp, jsonResp)
+	}
+	_, standard := postRaw(t, Encrypt, "key=0x2D55&output_encoding=hex", []byte{0xA7, 0x49})
+	if reflect.DeepEqual(standard["data"], rawResp["data"]) {
+		t.Fatal("1 轮与标准 2 轮的密文不应相同")
+	}
+	if code, _ := postRaw(t, Encrypt, "key=0x2D55&engine=bogus", []byte{0xA7, 0x49}); code != http.StatusBadRequest {
+		t.Fatalf("未知 engine 应返回 400，得到 %d", code)
+	}
+}
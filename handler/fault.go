@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"net/http"
+
+	"S-AES/models"
+	"S-AES/utils"
+	"S-AES/utils/fault"
+	"S-AES/utils/saes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxListedRoundKeys 限制响应中列出的最后一轮轮密钥候选个数。
+const maxListedRoundKeys = 256
+
+func SimulateFaults(c *gin.Context) {
+	cipher, cfg, ok := bindFaultRequest(c)
+	if !ok {
+		return
+	}
+
+	pairs, err := fault.Collect(cipher, cfg)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+
+	resp := models.FaultSimulationResponse{
+		Kind:     string(cfg.Kind),
+		Location: "before " + cfg.Before.String(),
+		Pairs:    make([]models.FaultPair, 0, len(pairs)),
+	}
+	for _, p := range pairs {
+		resp.Pairs = append(resp.Pairs, models.FaultPair{
+			Plaintext:  utils.FormatHex16(p.Plaintext),
+			Correct:    utils.FormatHex16(p.Correct),
+			Faulty:     utils.FormatHex16(p.Faulty),
+			Mask:       utils.FormatHex16(p.Mask),
+			Difference: utils.FormatHex16(p.Correct ^ p.Faulty),
+		})
+	}
+	respondSuccess(c, resp)
+}
+
+func DifferentialFaultAnalysis(c *gin.Context) {
+	cipher, cfg, ok := bindFaultRequest(c)
+	if !ok {
+		return
+	}
+
+	pairs, err := fault.Collect(cipher, cfg)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	result, err := fault.DFA(cipher, cfg, pairs)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+
+	resp := models.DFAResponse{
+		Kind:                   string(cfg.Kind),
+		Location:               "before " + cfg.Before.String(),
+		Pairs:                  len(pairs),
+		LastRoundKeyCandidates: len(result.LastRoundKeys),
+		LastRoundKeys:          make([]string, 0),
+		MasterKeys:             make([]string, 0, len(result.MasterKeys)),
+		CorrectKey:             utils.FormatHex16(cfg.Key),
+		Success:                len(result.MasterKeys) == 1 && result.MasterKeys[0] == cfg.Key,
+	}
+	for i, k := range result.LastRoundKeys {
+		if i == maxListedRoundKeys {
+			break
+		}
+		resp.LastRoundKeys = append(resp.LastRoundKeys, utils.FormatHex16(k))
+	}
+	for _, k := range result.MasterKeys {
+		resp.MasterKeys = append(resp.MasterKeys, utils.FormatHex16(k))
+	}
+	for _, p := range result.Progress {
+		resp.Progress = append(resp.Progress, models.DFAProgress{Pairs: p.Pairs, Candidates: p.Candidates})
+	}
+	respondSuccess(c, resp)
+}
+
+func bindFaultRequest(c *gin.Context) (*saes.Cipher, fault.Config, bool) {
+	var req models.FaultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return nil, fault.Config{}, false
+	}
+
+	cipher, err := buildGenericCipher(req.Cipher)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return nil, fault.Config{}, false
+	}
+	key, err := utils.ParseBlockString(req.Key)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, "无法解析密钥: "+err.Error())
+		return nil, fault.Config{}, false
+	}
+	kind, err := fault.ParseKind(req.Kind)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return nil, fault.Config{}, false
+	}
+
+	cfg := fault.Config{
+		Key:    key,
+		Kind:   kind,
+		Before: fault.DefaultLocation(cipher, kind),
+		Nibble: -1,
+		Bit:    -1,
+		Count:  req.Count,
+		Seed:   req.Seed,
+	}
+	if cfg.Count == 0 {
+		cfg.Count = 16
+	}
+	if req.Location != nil {
+		step, err := saes.ParseStep(req.Location.Step)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, 1, err.Error())
+			return nil, fault.Config{}, false
+		}
+		cfg.Before = saes.Point{Round: req.Location.Round, Step: step}
+	}
+	if req.Nibble != nil {
+		cfg.Nibble = *req.Nibble
+	}
+	if req.Bit != nil {
+		cfg.Bit = *req.Bit
+	}
+	return cipher, cfg, true
+}
@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+
+	"S-AES/models"
+	"S-AES/utils"
+	"S-AES/utils/keystore"
+	"S-AES/utils/saes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// containerExt 为加密后容器文件的扩展名。
+const containerExt = ".saes"
+
+// EncryptFile 把上传的文件流式加密为自描述容器并作为附件返回。
+func EncryptFile(c *gin.Context) {
+	var req models.FileEncryptRequest
+	if err := c.ShouldBind(&req); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	file, err := c.FormFile("file")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, "缺少上传文件 file")
+		return
+	}
+
+	mode := saes.ModeCBC
+	if strings.TrimSpace(req.Mode) != "" {
+		if mode, err = saes.ParseMode(req.Mode); err != nil {
+			respondError(c, http.StatusBadRequest, 1, err.Error())
+			return
+		}
+	}
+	padding, err := saes.ParsePadding(req.Padding)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	// 容器头在加密开始时就已写出，无法填充的输入必须在此之前拒绝。
+	if !mode.Streaming() && padding == saes.PaddingNone && file.Size%2 != 0 {
+		respondError(c, http.StatusBadRequest, 1, "padding 为 none 时文件长度必须为偶数")
+		return
+	}
+	kb, macKey, stored, ok := buildFileKeys(c, req.Key, req.PasswordKey, req.KeyID, req.MACKey, req.Engine)
+	if !ok {
+		return
+	}
+	opts := saes.StreamOptions{Mode: mode, Padding: padding, KeyID: req.KeyID, MACKey: macKey}
+	if stored != nil {
+		opts.KeyID = stored.Ref()
+	}
+	if strings.TrimSpace(req.IV) != "" {
+		iv, err := utils.ParseBlockString(req.IV)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, 1, "无法解析初始向量: "+err.Error())
+			return
+		}
+		opts.IV = &iv
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, 1, err.Error())
+		return
+	}
+	defer src.Close()
+
+	c.Header("Content-Type", "application/octet-stream")
+	c.Header("Content-Disposition", attachmentDisposition(file.Filename+containerExt))
+	ew, err := saes.NewEncryptWriter(c.Writer, kb, opts)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	if _, err := io.Copy(ew, src); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if err := ew.Close(); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+// attachmentDisposition 按 RFC 6266 转义文件名，非 ASCII 文件名以 filename* 编码。
+func attachmentDisposition(filename string) string {
+	if v := mime.FormatMediaType("attachment", map[string]string{"filename": filename}); v != "" {
+		return v
+	}
+	return "attachment"
+}
+
+// DecryptFile 解密上传的容器文件。明文先写入临时文件，MAC 校验通过后才返回，避免输出被篡改的数据。
+func DecryptFile(c *gin.Context) {
+	var req models.FileDecryptRequest
+	if err := c.ShouldBind(&req); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	file, err := c.FormFile("file")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, "缺少上传文件 file")
+		return
+	}
+	src, err := file.Open()
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, 1, err.Error())
+		return
+	}
+	defer src.Close()
+
+	// 未提供密钥时按容器头部记录的 Key-ID 查找密钥库。
+	var header *saes.Header
+	keyRef := ""
+	if strings.TrimSpace(req.Key) == "" && req.Password == "" {
+		if header, err = saes.ReadHeader(src); err != nil {
+			respondError(c, http.StatusBadRequest, 1, err.Error())
+			return
+		}
+		if header.KeyID == "" {
+			respondError(c, http.StatusBadRequest, 1, "未提供密钥，且容器未记录 Key-ID")
+			return
+		}
+		keyRef = header.KeyID
+	}
+	kb, macKey, _, ok := buildFileKeys(c, req.Key, req.PasswordKey, keyRef, req.MACKey, req.Engine)
+	if !ok {
+		return
+	}
+
+	var dr *saes.DecryptReader
+	if header != nil {
+		dr, err = saes.NewDecryptReaderFromHeader(src, header, kb, macKey)
+	} else {
+		dr, err = saes.NewDecryptReader(src, kb, macKey)
+	}
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	tmp, err := os.CreateTemp("", "saes-decrypt-*")
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, 1, err.Error())
+		return
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if _, err := io.Copy(tmp, dr); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, saes.ErrMACMismatch) {
+			status = http.StatusUnprocessableEntity
+		}
+		respondError(c, status, 1, err.Error())
+		return
+	}
+
+	h := dr.Header()
+	c.Header("X-SAES-Mode", string(h.Mode))
+	c.Header("X-SAES-Padding", string(h.Padding))
+	c.Header("X-SAES-Key-Bits", fmt.Sprint(h.KeyBits))
+	if h.KeyID != "" {
+		c.Header("X-SAES-Key-ID", h.KeyID)
+	}
+	name := strings.TrimSuffix(file.Filename, containerExt)
+	if name == file.Filename {
+		name += ".dec"
+	}
+	c.FileAttachment(tmp.Name(), name)
+}
+
+// buildFileKeys 构造文件接口的密钥。key 与 password 均未提供时使用密钥库中的 keyRef，
+// 此时 stored 为所用的密钥版本。
+func buildFileKeys(c *gin.Context, key string, pk models.PasswordKey, keyRef, macKeyText, engine string) (kb, macKey *saes.KeyedBlock, stored *keystore.Key, ok bool) {
+	bc, err := buildCipher(engineOptions(engine))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return nil, nil, nil, false
+	}
+	if strings.TrimSpace(key) != "" || pk.Password != "" {
+		keyRef = ""
+	}
+	if kb, stored, err = requestKey(bc, key, keyRef, pk, 0); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return nil, nil, nil, false
+	}
+	if strings.TrimSpace(macKeyText) != "" {
+		if macKey, err = saes.NewKeyedBlock(bc, macKeyText); err != nil {
+			respondError(c, http.StatusBadRequest, 1, "MAC 密钥: "+err.Error())
+			return nil, nil, nil, false
+		}
+	}
+	return kb, macKey, stored, true
+}
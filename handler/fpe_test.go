@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestEncryptFPERejectsNegativeDomain(t *testing.T) {
+	code, resp := postJSON(t, EncryptFPE, `{"plaintext":"1234","key":"0x1234","domain":-1}`)
+	if code != http.StatusBadRequest || resp["code"] != float64(1) {
+		t.Fatalf("负数 domain 应返回 400，得到 %d %v", code, resp)
+	}
+
+	code, resp = postJSON(t, EncryptFPE, `{"plaintext":"1234","key":"0x2D55"}`)
+	data, _ := resp["data"].(map[string]any)
+	if code != http.StatusOK || data["ciphertext"] != "9466" || data["radix"] != float64(10) {
+		t.Fatalf("默认十进制字母表的加密结果不符: %d %v", code, resp)
+	}
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"S-AES/models"
+	"S-AES/utils/codec"
+	"S-AES/utils/saeshash"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultHashAttempts 为碰撞与第二原像搜索默认的尝试次数。
+	defaultHashAttempts = 1 << 20
+	// maxHashAttempts 限制单次搜索的尝试次数，32 位第二原像的期望 2^32 次不在范围内。
+	maxHashAttempts = 1 << 24
+)
+
+// HashMessage 用所选的分组密码哈希构造计算摘要，默认输入为文本、输出为十六进制。
+func HashMessage(c *gin.Context) {
+	var req models.HashRequest
+	raw, ok := bindCryptRequest(c, &req)
+	if !ok {
+		return
+	}
+	cfg, ok := hashConfig(c, req.Construction, req.Bits, req.Cipher)
+	if !ok {
+		return
+	}
+	outEnc, err := outputEncoding(req.OutputEncoding, codec.Hex, false)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	msg, err := decodeInput(raw, req.Message, req.InputEncoding, codec.Text, "消息")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	sum, err := saeshash.Sum(cfg, msg)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	respondCrypt(c, "digest", "", sum, outEnc, gin.H{"construction": cfg.Construction, "bits": cfg.Bits})
+}
+
+// FindHashCollision 用生日攻击寻找两条摘要相同的消息，16 位哈希通常只需几百次尝试。
+func FindHashCollision(c *gin.Context) {
+	var req models.HashCollisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	cfg, ok := hashConfig(c, req.Construction, req.Bits, req.Cipher)
+	if !ok {
+		return
+	}
+	attempts, ok := hashAttempts(c, req.MaxAttempts)
+	if !ok {
+		return
+	}
+
+	start := time.Now()
+	res, err := saeshash.FindCollision(cfg, []byte(req.Prefix), attempts)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	out := gin.H{
+		"construction": cfg.Construction,
+		"bits":         cfg.Bits,
+		"found":        res.Found,
+		"attempts":     res.Attempts,
+		"expected":     res.Expected,
+		"elapsed_ms":   durationMs(time.Since(start)),
+	}
+	if res.Found {
+		out["first"] = hex.EncodeToString(res.First)
+		out["second"] = hex.EncodeToString(res.Second)
+		out["digest"] = hex.EncodeToString(res.Digest)
+	}
+	respondSuccess(c, out)
+}
+
+// FindHashSecondPreimage 寻找与给定消息摘要相同的另一条等长消息，期望尝试次数为 2^n。
+func FindHashSecondPreimage(c *gin.Context) {
+	var req models.HashPreimageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	cfg, ok := hashConfig(c, req.Construction, req.Bits, req.Cipher)
+	if !ok {
+		return
+	}
+	attempts, ok := hashAttempts(c, req.MaxAttempts)
+	if !ok {
+		return
+	}
+	target, err := decodeInput(nil, req.Message, req.InputEncoding, codec.Text, "消息")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+
+	start := time.Now()
+	res, err := saeshash.FindSecondPreimage(cfg, target, attempts)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	out := gin.H{
+		"construction": cfg.Construction,
+		"bits":         cfg.Bits,
+		"target":       hex.EncodeToString(res.Target),
+		"digest":       hex.EncodeToString(res.Digest),
+		"found":        res.Found,
+		"attempts":     res.Attempts,
+		"expected":     res.Expected,
+		"elapsed_ms":   durationMs(time.Since(start)),
+	}
+	if res.Found {
+		out["message"] = hex.EncodeToString(res.Message)
+	}
+	respondSuccess(c, out)
+}
+
+func hashConfig(c *gin.Context, construction string, bits int, cipher *models.CipherOptions) (saeshash.Config, bool) {
+	cons, err := saeshash.ParseConstruction(construction)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return saeshash.Config{}, false
+	}
+	if bits == 0 {
+		bits = 16
+	}
+	cfg := saeshash.Config{Construction: cons, Bits: bits}
+	if cipher != nil {
+		if cfg.Cipher, err = buildCipher(cipher); err != nil {
+			respondError(c, http.StatusBadRequest, 1, err.Error())
+			return saeshash.Config{}, false
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return saeshash.Config{}, false
+	}
+	return cfg, true
+}
+
+func hashAttempts(c *gin.Context, n int) (int, bool) {
+	if n == 0 {
+		return defaultHashAttempts, true
+	}
+	if n < 1 || n > maxHashAttempts {
+		respondError(c, http.StatusBadRequest, 1, fmt.Sprintf("max_attempts 必须在 1 到 %d 之间", maxHashAttempts))
+		return 0, false
+	}
+	return n, true
+}
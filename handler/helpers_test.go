@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// postJSON 把 body 以 JSON 提交给 h，返回状态码与解析后的响应。
+func postJSON(t *testing.T, h gin.HandlerFunc, body string) (int, map[string]any) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	h(c)
+	var resp map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("响应不是 JSON: %s", w.Body.String())
+	}
+	return w.Code, resp
+}
+
+// postRaw 以 application/octet-stream 把 body 提交给 h，参数位于 query 中。
+func postRaw(t *testing.T, h gin.HandlerFunc, query string, body []byte) (int, map[string]any) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/?"+query, bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", octetStream)
+	h(c)
+	var resp map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("响应不是 JSON: %s", w.Body.String())
+	}
+	return w.Code, resp
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"S-AES/models"
+	"S-AES/utils/imagecrypt"
+	"S-AES/utils/saes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImageBytes 限制上传图像文件的大小。
+const maxImageBytes = 64 << 20
+
+// EncryptImage 加密上传的未压缩 BMP 或 PNG 的像素数据，返回同格式、可直接查看的图像。
+// 使用标准 S-AES；CBC 等模式的初始向量写入 X-SAES-IV 响应头。
+func EncryptImage(c *gin.Context) {
+	var req models.ImageEncryptRequest
+	if err := c.ShouldBind(&req); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	file, err := c.FormFile("file")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, "缺少上传文件 file")
+		return
+	}
+	if file.Size > maxImageBytes {
+		respondError(c, http.StatusRequestEntityTooLarge, 1, fmt.Sprintf("图像文件过大（至多 %d 字节）", maxImageBytes))
+		return
+	}
+
+	mode := saes.ModeCBC
+	if strings.TrimSpace(req.Mode) != "" {
+		if mode, err = saes.ParseMode(req.Mode); err != nil {
+			respondError(c, http.StatusBadRequest, 1, err.Error())
+			return
+		}
+	}
+	kb, _, err := requestKey(saes.Standard, req.Key, req.KeyID, req.PasswordKey, 0)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	var iv uint16
+	if mode.NeedsIV() {
+		if iv, err = requestIV(req.IV); err != nil {
+			respondError(c, http.StatusBadRequest, 1, err.Error())
+			return
+		}
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, 1, err.Error())
+		return
+	}
+	defer src.Close()
+	data, err := io.ReadAll(io.LimitReader(src, maxImageBytes))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, 1, err.Error())
+		return
+	}
+
+	out, format, err := imagecrypt.Encrypt(data, kb, mode, iv)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, imagecrypt.ErrUnsupportedFormat) {
+			status = http.StatusUnsupportedMediaType
+		}
+		respondError(c, status, 1, err.Error())
+		return
+	}
+	if mode.NeedsIV() {
+		c.Header("X-SAES-IV", fmt.Sprintf("0x%04X", iv))
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s-encrypted.%s"`, mode, format))
+	c.Data(http.StatusOK, format.ContentType(), out)
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"S-AES/models"
+	"S-AES/utils"
+	"S-AES/utils/kdf"
+	"S-AES/utils/saes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxCrackPairs 限制破解演示生成的明密文对数量。
+const maxCrackPairs = 16
+
+// DeriveKDF 由口令与盐派生 S-AES 密钥，可选地立即对派生密钥运行攻击演示。
+func DeriveKDF(c *gin.Context) {
+	var req models.KDFDeriveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+
+	alg, err := kdf.ParseAlgorithm(req.Algorithm)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	params, err := kdf.Params{
+		Algorithm:   alg,
+		Iterations:  req.Iterations,
+		N:           req.N,
+		R:           req.R,
+		Memory:      req.Memory,
+		Parallelism: req.Parallelism,
+	}.Resolve()
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	bits := req.KeyBits
+	if bits == 0 {
+		bits = 16
+	}
+	if req.CrackPairs < 0 || req.CrackPairs > maxCrackPairs {
+		respondError(c, http.StatusBadRequest, 1, fmt.Sprintf("crack_pairs 必须在 0 到 %d 之间", maxCrackPairs))
+		return
+	}
+
+	var salt []byte
+	if strings.TrimSpace(req.Salt) == "" {
+		salt, err = kdf.NewSalt()
+	} else {
+		salt, err = kdf.ParseSalt(req.Salt)
+	}
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+
+	start := time.Now()
+	key, err := kdf.Derive([]byte(req.Password), salt, bits, params)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	deriveMs := durationMs(time.Since(start))
+
+	resp := models.KDFDeriveResponse{
+		Key:       kdf.FormatKey(key),
+		KeyBits:   bits,
+		Salt:      kdf.FormatKey(salt),
+		Algorithm: string(params.Algorithm),
+		DeriveMs:  deriveMs,
+	}
+	switch params.Algorithm {
+	case kdf.PBKDF2:
+		resp.Params = models.KDFParams{Iterations: params.Iterations}
+	case kdf.Scrypt:
+		resp.Params = models.KDFParams{N: params.N, R: params.R, Parallelism: params.Parallelism}
+	case kdf.Argon2id:
+		resp.Params = models.KDFParams{Iterations: params.Iterations, Memory: params.Memory, Parallelism: params.Parallelism}
+	}
+
+	if req.Crack {
+		bc, err := buildCipher(req.Cipher)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, 1, err.Error())
+			return
+		}
+		result, err := kdf.Crack(bc, key, req.CrackPairs)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, 1, err.Error())
+			return
+		}
+		report := &models.KDFCrackReport{
+			Attack:          result.Attack,
+			Pairs:           make([]models.AttackPair, 0, len(result.Pairs)),
+			Candidates:      result.Candidates,
+			Recovered:       result.Recovered,
+			Keyspace:        result.Keyspace,
+			ElapsedMs:       durationMs(result.Elapsed),
+			PasswordGuessMs: deriveMs,
+		}
+		for _, p := range result.Pairs {
+			report.Pairs = append(report.Pairs, models.AttackPair{
+				Plaintext:  utils.FormatHex16(p.Plain),
+				Ciphertext: utils.FormatHex16(p.Cipher),
+			})
+		}
+		if deriveMs > 0 {
+			report.GuessesInSameTime = report.ElapsedMs / deriveMs
+		}
+		resp.Crack = report
+	}
+
+	respondSuccess(c, resp)
+}
+
+// resolveKey 返回请求实际使用的密钥：直接给出的 key，或由 password 与 salt 派生的密钥。
+func resolveKey(key string, pk models.PasswordKey) (string, error) {
+	hasKey := strings.TrimSpace(key) != ""
+	hasPassword := pk.Password != ""
+	switch {
+	case hasKey && hasPassword:
+		return "", fmt.Errorf("key 与 password 只能提供其一")
+	case hasKey:
+		return key, nil
+	case !hasPassword:
+		return "", fmt.Errorf("密钥不能为空（可提供 key，或 password 与 salt）")
+	}
+
+	if strings.TrimSpace(pk.Salt) == "" {
+		return "", fmt.Errorf("使用口令派生密钥时盐不能为空")
+	}
+	salt, err := kdf.ParseSalt(pk.Salt)
+	if err != nil {
+		return "", err
+	}
+	alg, err := kdf.ParseAlgorithm(pk.KDF)
+	if err != nil {
+		return "", err
+	}
+	bits := pk.KeyBits
+	if bits == 0 {
+		bits = 16
+	}
+	return kdf.DeriveKey([]byte(pk.Password), salt, bits, kdf.DefaultParams(alg))
+}
+
+// requestKeyedBlock 解析请求中的 key 或口令并与 bc 绑定。
+func requestKeyedBlock(bc saes.BlockCipher, key string, pk models.PasswordKey) (*saes.KeyedBlock, error) {
+	resolved, err := resolveKey(key, pk)
+	if err != nil {
+		return nil, err
+	}
+	return saes.NewKeyedBlock(bc, resolved)
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d.Nanoseconds()) / 1e6
+}
@@ -0,0 +1,344 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"S-AES/models"
+	"S-AES/utils"
+	"S-AES/utils/armor"
+	"S-AES/utils/codec"
+	"S-AES/utils/keystore"
+	"S-AES/utils/saes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// keyStore 为服务端密钥库，默认仅驻留内存，可由 SetKeyStore 替换为加密文件。
+var keyStore = keystore.NewMemory()
+
+// SetKeyStore 替换服务端密钥库，须在路由开始处理请求前调用。
+func SetKeyStore(s *keystore.Store) {
+	keyStore = s
+}
+
+// CreateKey 生成新的命名密钥（版本 1），响应中不包含密钥材料。
+func CreateKey(c *gin.Context) {
+	var req models.KeyCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	bits := req.Bits
+	if bits == 0 {
+		bits = 16
+	}
+	key, err := keyStore.Create(req.Name, bits)
+	if err != nil {
+		respondKeyStoreError(c, err)
+		return
+	}
+	info, err := keyStore.Info(key.Name)
+	if err != nil {
+		respondKeyStoreError(c, err)
+		return
+	}
+	respondSuccess(c, info)
+}
+
+func ListKeys(c *gin.Context) {
+	respondSuccess(c, keyStore.List())
+}
+
+func GetKey(c *gin.Context) {
+	info, err := keyStore.Info(c.Param("name"))
+	if err != nil {
+		respondKeyStoreError(c, err)
+		return
+	}
+	respondSuccess(c, info)
+}
+
+// RotateKey 生成新版本并设为当前版本，旧版本保留用于解密。请求体可以为空（包括长度未知的分块请求），
+// 此时沿用当前版本的位数。
+func RotateKey(c *gin.Context) {
+	var req models.KeyRotateRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	key, err := keyStore.Rotate(c.Param("name"), req.Bits)
+	if err != nil {
+		respondKeyStoreError(c, err)
+		return
+	}
+	info, err := keyStore.Info(key.Name)
+	if err != nil {
+		respondKeyStoreError(c, err)
+		return
+	}
+	respondSuccess(c, info)
+}
+
+func DeleteKey(c *gin.Context) {
+	if err := keyStore.Delete(c.Param("name")); err != nil {
+		respondKeyStoreError(c, err)
+		return
+	}
+	respondSuccess(c, gin.H{"deleted": c.Param("name")})
+}
+
+// RewrapCiphertexts 用密文记录的旧版本解密、再以当前版本重新加密。
+// 两个方向都不处理填充，分组模式的密文本就是整数个分组，因此无需知道原来的填充方式。
+func RewrapCiphertexts(c *gin.Context) {
+	var req models.RewrapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	bc, err := buildCipher(req.Cipher)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	name, _, err := keystore.ParseRef(req.KeyID)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	current, err := keyStore.Get(name, 0)
+	if err != nil {
+		respondKeyStoreError(c, err)
+		return
+	}
+	newKB, err := saes.NewKeyedBlock(bc, current.Hex())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, 1, err.Error())
+		return
+	}
+
+	mode := saes.ModeECB
+	if strings.TrimSpace(req.Mode) != "" {
+		if mode, err = saes.ParseMode(req.Mode); err != nil {
+			respondError(c, http.StatusBadRequest, 1, err.Error())
+			return
+		}
+	}
+	var iv uint16
+	if strings.TrimSpace(req.IV) != "" {
+		if iv, err = utils.ParseBlockString(req.IV); err != nil {
+			respondError(c, http.StatusBadRequest, 1, "无法解析初始向量: "+err.Error())
+			return
+		}
+	}
+	inEnc, err := codec.Parse(req.InputEncoding, codec.Base64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	outEnc, err := codec.Parse(req.OutputEncoding, inEnc)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	if inEnc == codec.Raw || outEnc == codec.Raw {
+		respondError(c, http.StatusBadRequest, 1, "该接口不支持 raw 编码")
+		return
+	}
+
+	results := make([]models.RewrapResult, 0, len(req.Ciphertexts))
+	for idx, text := range req.Ciphertexts {
+		var result models.RewrapResult
+		if armor.IsArmored(text) {
+			result, err = rewrapArmored(bc, name, current, newKB, text)
+		} else {
+			result, err = rewrapPrefixed(bc, name, current, newKB, mode, iv, inEnc, outEnc, text)
+		}
+		if err != nil {
+			respondError(c, http.StatusBadRequest, 1, fmt.Sprintf("第 %d 条密文: %v", idx+1, err))
+			return
+		}
+		results = append(results, result)
+	}
+
+	respondSuccess(c, models.RewrapResponse{
+		KeyID:          current.Name,
+		CurrentVersion: current.Version,
+		Results:        results,
+	})
+}
+
+func rewrapPrefixed(bc saes.BlockCipher, name string, current *keystore.Key, newKB *saes.KeyedBlock,
+	mode saes.Mode, iv uint16, inEnc, outEnc codec.Encoding, text string) (models.RewrapResult, error) {
+	body, version := keystore.SplitPrefix(text)
+	if version == 0 {
+		return models.RewrapResult{}, fmt.Errorf("缺少 %sN: 版本前缀", keystore.PrefixTag)
+	}
+	oldKB, err := storedKeyedBlock(bc, name, version)
+	if err != nil {
+		return models.RewrapResult{}, err
+	}
+	data, err := codec.Decode(inEnc, body)
+	if err != nil {
+		return models.RewrapResult{}, err
+	}
+	plain, err := saes.DecryptBytes(oldKB, mode, saes.PaddingNone, iv, data)
+	if err != nil {
+		return models.RewrapResult{}, err
+	}
+	data, err = saes.EncryptBytes(newKB, mode, saes.PaddingNone, iv, plain)
+	if err != nil {
+		return models.RewrapResult{}, err
+	}
+	out, err := codec.Encode(outEnc, data)
+	if err != nil {
+		return models.RewrapResult{}, err
+	}
+	return models.RewrapResult{
+		Ciphertext:  keystore.Prefix(current.Version) + out,
+		FromVersion: version,
+		ToVersion:   current.Version,
+	}, nil
+}
+
+func rewrapArmored(bc saes.BlockCipher, name string, current *keystore.Key, newKB *saes.KeyedBlock, text string) (models.RewrapResult, error) {
+	m, err := armor.Decode(text)
+	if err != nil {
+		return models.RewrapResult{}, err
+	}
+	refName, version, err := keystore.ParseRef(m.KeyID)
+	if err != nil || version == 0 {
+		return models.RewrapResult{}, fmt.Errorf("ASCII 封装的 Key-ID 未记录密钥版本")
+	}
+	if refName != name {
+		return models.RewrapResult{}, fmt.Errorf("ASCII 封装属于密钥 %s，而非 %s", refName, name)
+	}
+	oldKB, err := storedKeyedBlock(bc, name, version)
+	if err != nil {
+		return models.RewrapResult{}, err
+	}
+	var iv uint16
+	if m.IV != nil {
+		iv = *m.IV
+	}
+	plain, err := saes.DecryptBytes(oldKB, m.Mode, saes.PaddingNone, iv, m.Data)
+	if err != nil {
+		return models.RewrapResult{}, err
+	}
+	if m.Data, err = saes.EncryptBytes(newKB, m.Mode, saes.PaddingNone, iv, plain); err != nil {
+		return models.RewrapResult{}, err
+	}
+	m.KeyBits = newKB.KeyBits()
+	m.KeyID = current.Ref()
+	return models.RewrapResult{
+		Ciphertext:  armor.Encode(m),
+		FromVersion: version,
+		ToVersion:   current.Version,
+	}, nil
+}
+
+// requestKey 解析请求中的 key、口令或 key_id（三者只能提供其一）。
+// version 为密文前缀或封装头记录的版本，仅对 key_id 生效，0 表示 key_id 指定的版本或当前版本。
+// 使用密钥库时 stored 为对应的密钥版本。
+func requestKey(bc saes.BlockCipher, key, keyID string, pk models.PasswordKey, version int) (kb *saes.KeyedBlock, stored *keystore.Key, err error) {
+	if strings.TrimSpace(keyID) == "" {
+		kb, err = requestKeyedBlock(bc, key, pk)
+		return kb, nil, err
+	}
+	if strings.TrimSpace(key) != "" || pk.Password != "" {
+		return nil, nil, fmt.Errorf("key_id 不能与 key 或 password 同时提供")
+	}
+	name, refVersion, err := keystore.ParseRef(keyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if version != 0 && refVersion != 0 && version != refVersion {
+		return nil, nil, fmt.Errorf("密文记录的版本 v%d 与 key_id 指定的 v%d 不一致", version, refVersion)
+	}
+	if version == 0 {
+		version = refVersion
+	}
+	if stored, err = keyStore.Get(name, version); err != nil {
+		return nil, nil, err
+	}
+	kb, err = saes.NewKeyedBlock(bc, stored.Hex())
+	return kb, stored, err
+}
+
+func storedKeyedBlock(bc saes.BlockCipher, name string, version int) (*saes.KeyedBlock, error) {
+	key, err := keyStore.Get(name, version)
+	if err != nil {
+		return nil, err
+	}
+	return saes.NewKeyedBlock(bc, key.Hex())
+}
+
+// storedKeyPrefix 在使用密钥库密钥时返回密文版本前缀，并在 extra 中记录密钥名称与版本。
+func storedKeyPrefix(stored *keystore.Key, extra gin.H) string {
+	if stored == nil {
+		return ""
+	}
+	extra["key_id"] = stored.Name
+	extra["key_version"] = stored.Version
+	return keystore.Prefix(stored.Version)
+}
+
+// storedKeyRef 返回写入容器或 ASCII 封装的 Key-ID，未使用密钥库时为空。
+func storedKeyRef(stored *keystore.Key) string {
+	if stored == nil {
+		return ""
+	}
+	return stored.Ref()
+}
+
+// cipherInput 为剥离版本前缀后的解密输入及对应密钥。armored 非空表示输入为 ASCII 封装。
+type cipherInput struct {
+	raw     []byte
+	text    string
+	armored string
+	kb      *saes.KeyedBlock
+}
+
+// resolveCipherInput 剥离密文的 "saes:vN:" 前缀，检测 ASCII 封装，并按记录的版本解析密钥。
+func resolveCipherInput(bc saes.BlockCipher, raw []byte, text, key, keyID string, pk models.PasswordKey) (*cipherInput, error) {
+	in := &cipherInput{raw: raw, text: text}
+	var version int
+	if raw != nil {
+		var rest string
+		if rest, version = keystore.SplitPrefix(string(raw)); version != 0 {
+			in.raw = []byte(rest)
+		}
+	} else {
+		in.text, version = keystore.SplitPrefix(text)
+	}
+
+	in.armored = armoredInput(in.raw, in.text)
+	if in.armored != "" && version == 0 {
+		if m, err := armor.Decode(in.armored); err == nil && m.KeyID != "" {
+			if _, v, err := keystore.ParseRef(m.KeyID); err == nil {
+				version = v
+			}
+		}
+	}
+
+	kb, _, err := requestKey(bc, key, keyID, pk, version)
+	if err != nil {
+		return nil, err
+	}
+	in.kb = kb
+	return in, nil
+}
+
+func respondKeyStoreError(c *gin.Context, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, keystore.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, keystore.ErrExists):
+		status = http.StatusConflict
+	}
+	respondError(c, status, 1, err.Error())
+}
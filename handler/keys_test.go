@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"S-AES/utils/keystore"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRotateKeyBody(t *testing.T) {
+	SetKeyStore(keystore.NewMemory())
+	if _, err := keyStore.Create("k", 32); err != nil {
+		t.Fatal(err)
+	}
+
+	rotate := func(body string, length int64) (int, map[string]any) {
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/keys/k/rotate", nil)
+		c.Request.Body = io.NopCloser(strings.NewReader(body))
+		c.Request.ContentLength = length
+		c.Params = gin.Params{{Key: "name", Value: "k"}}
+		RotateKey(c)
+		var resp map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("响应不是 JSON: %s", w.Body.String())
+		}
+		return w.Code, resp
+	}
+
+	// 长度未知（分块传输）的空请求体等同于没有参数，沿用当前位数。
+	code, resp := rotate("", -1)
+	data, _ := resp["data"].(map[string]any)
+	if code != http.StatusOK || data["current"] != float64(2) {
+		t.Fatalf("空的分块请求应成功轮换: %d %v", code, resp)
+	}
+	versions := data["versions"].([]any)
+	if bits := versions[1].(map[string]any)["bits"]; bits != float64(32) {
+		t.Fatalf("新版本位数为 %v，期望沿用 32", bits)
+	}
+
+	if code, resp = rotate(`{"bits":16}`, -1); code != http.StatusOK {
+		t.Fatalf("分块传输的 JSON 请求体应被解析: %d %v", code, resp)
+	}
+	if code, _ = rotate(`{"bits":`, -1); code != http.StatusBadRequest {
+		t.Fatalf("截断的 JSON 应返回 400，得到 %d", code)
+	}
+}
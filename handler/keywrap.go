@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"S-AES/models"
+	"S-AES/utils/codec"
+	"S-AES/utils/keystore"
+	"S-AES/utils/saes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WrapKey 用 KEK 包装密钥材料（KW 或 KWP），默认输入输出均为十六进制。
+func WrapKey(c *gin.Context) {
+	var req models.KeyWrapRequest
+	raw, ok := bindCryptRequest(c, &req)
+	if !ok {
+		return
+	}
+
+	bc, err := buildCipher(req.Cipher)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	kek, stored, err := requestKey(bc, req.Key, req.KeyID, req.PasswordKey, 0)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	outEnc, err := outputEncoding(req.OutputEncoding, codec.Hex, false)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+
+	var material []byte
+	if strings.TrimSpace(req.WrapKeyID) != "" {
+		if raw != nil || req.Plaintext != "" {
+			respondError(c, http.StatusBadRequest, 1, "wrap_key_id 不能与 plaintext 同时提供")
+			return
+		}
+		name, version, err := keystore.ParseRef(req.WrapKeyID)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, 1, err.Error())
+			return
+		}
+		key, err := keyStore.Get(name, version)
+		if err != nil {
+			respondKeyStoreError(c, err)
+			return
+		}
+		material = key.Material
+	} else if material, err = decodeInput(raw, req.Plaintext, req.InputEncoding, codec.Hex, "待包装数据"); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+
+	algorithm := "kw"
+	wrap := saes.WrapKey
+	if req.Padded {
+		algorithm = "kwp"
+		wrap = saes.WrapKeyPad
+	}
+	wrapped, err := wrap(kek, material)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+
+	extra := gin.H{"algorithm": algorithm}
+	prefix := storedKeyPrefix(stored, extra)
+	respondCrypt(c, "ciphertext", prefix, wrapped, outEnc, extra)
+}
+
+// UnwrapKey 解包并校验完整性，校验失败返回 422。
+func UnwrapKey(c *gin.Context) {
+	var req models.KeyUnwrapRequest
+	raw, ok := bindCryptRequest(c, &req)
+	if !ok {
+		return
+	}
+
+	bc, err := buildCipher(req.Cipher)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	outEnc, err := outputEncoding(req.OutputEncoding, codec.Hex, false)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	in, err := resolveCipherInput(bc, raw, req.Ciphertext, req.Key, req.KeyID, req.PasswordKey)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	if in.armored != "" {
+		respondError(c, http.StatusBadRequest, 1, "密钥包装数据不使用 ASCII 封装")
+		return
+	}
+	wrapped, err := decodeInput(in.raw, in.text, req.InputEncoding, codec.Hex, "包装数据")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+
+	unwrap := saes.UnwrapKey
+	if req.Padded {
+		unwrap = saes.UnwrapKeyPad
+	}
+	material, err := unwrap(in.kb, wrapped)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, saes.ErrUnwrap) {
+			status = http.StatusUnprocessableEntity
+		}
+		respondError(c, status, 1, err.Error())
+		return
+	}
+
+	respondCrypt(c, "plaintext", "", material, outEnc, nil)
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"S-AES/models"
+	"S-AES/utils/prp"
+	"S-AES/utils/saes"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultPRPPageSize 为置换表分页未指定 limit 时的每页项数。
+	defaultPRPPageSize = 1024
+	// maxPRPWalks 为逐个循环游走的不同下标个数上限，超过时改为整表计算后查表。
+	maxPRPWalks = 16
+)
+
+// PermutePRP 计算 [0, size) 上带密钥置换的 π(i) 或 π⁻¹(i)。
+func PermutePRP(c *gin.Context) {
+	var req models.PRPPermuteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	kb, ok := prpKey(c, req.Key, req.KeyID, req.PasswordKey, req.Cipher)
+	if !ok {
+		return
+	}
+	p, err := prp.New(kb, req.Size)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	if len(req.Indices) > req.Size {
+		respondError(c, http.StatusBadRequest, 1, "indices 的个数不能超过 size")
+		return
+	}
+	values, err := permuteIndices(p, req.Indices, req.Inverse)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	respondSuccess(c, gin.H{"size": req.Size, "inverse": req.Inverse, "values": values})
+}
+
+// permuteIndices 对去重后的下标求值。size 很小时单次循环游走可能要加密上万次，
+// 不同下标较多时改为一次算出整张表（总加密次数约 2^16）再查表。
+func permuteIndices(p *prp.Permutation, indices []int, inverse bool) ([]int, error) {
+	distinct := make(map[int]int, len(indices))
+	for _, i := range indices {
+		if i < 0 || i >= p.Size() {
+			return nil, fmt.Errorf("%d 不在定义域 [0, %d) 内", i, p.Size())
+		}
+		distinct[i] = 0
+	}
+	if len(distinct) > maxPRPWalks {
+		table, err := p.Page(0, p.Size())
+		if err != nil {
+			return nil, err
+		}
+		if inverse {
+			for i, v := range table {
+				if _, ok := distinct[v]; ok {
+					distinct[v] = i
+				}
+			}
+		} else {
+			for i := range distinct {
+				distinct[i] = table[i]
+			}
+		}
+	} else {
+		apply := p.Permute
+		if inverse {
+			apply = p.Invert
+		}
+		for i := range distinct {
+			v, err := apply(i)
+			if err != nil {
+				return nil, err
+			}
+			distinct[i] = v
+		}
+	}
+	values := make([]int, len(indices))
+	for k, i := range indices {
+		values[k] = distinct[i]
+	}
+	return values, nil
+}
+
+// ShufflePRP 按密钥确定性地重排列表，同一密钥对同样长度的列表总得到相同顺序。
+func ShufflePRP(c *gin.Context) {
+	var req models.PRPShuffleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	kb, ok := prpKey(c, req.Key, req.KeyID, req.PasswordKey, req.Cipher)
+	if !ok {
+		return
+	}
+	shuffle := prp.Shuffle[json.RawMessage]
+	if req.Inverse {
+		shuffle = prp.Unshuffle[json.RawMessage]
+	}
+	items, err := shuffle(kb, req.Items)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	respondSuccess(c, gin.H{"items": items})
+}
+
+// PermutationTable 分页返回置换表；format 为 csv 或 jsonl 时流式输出。
+func PermutationTable(c *gin.Context) {
+	var req models.PRPTableRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	kb, ok := prpKey(c, req.Key, req.KeyID, req.PasswordKey, engineOptions(req.Engine))
+	if !ok {
+		return
+	}
+	p, err := prp.New(kb, req.Size)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	if req.Offset < 0 || req.Offset > req.Size || req.Limit < 0 {
+		respondError(c, http.StatusBadRequest, 1, "offset 必须在 0 到 size 之间，limit 不能为负数")
+		return
+	}
+	// 超出表尾的 limit 没有意义，先截断以免偏移相加溢出。
+	req.Limit = min(req.Limit, req.Size-req.Offset)
+
+	var write func() error
+	switch strings.ToLower(strings.TrimSpace(req.Format)) {
+	case "", "json":
+		limit := req.Limit
+		if limit == 0 {
+			limit = defaultPRPPageSize
+		}
+		values, err := p.Page(req.Offset, limit)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, 1, err.Error())
+			return
+		}
+		page := models.PRPPage{Size: req.Size, Offset: req.Offset, Values: values}
+		if next := req.Offset + len(values); next < req.Size {
+			page.NextOffset = &next
+		}
+		respondSuccess(c, page)
+		return
+	case "csv":
+		c.Header("Content-Type", "text/csv")
+		c.Header("Content-Disposition", `attachment; filename="permutation.csv"`)
+		write = func() error { return p.WriteCSV(c.Writer, req.Offset, req.Limit) }
+	case "jsonl":
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Header("Content-Disposition", `attachment; filename="permutation.jsonl"`)
+		write = func() error { return p.WriteJSONL(c.Writer, req.Offset, req.Limit) }
+	default:
+		respondError(c, http.StatusBadRequest, 1, "不支持的格式（可选 json、csv、jsonl）")
+		return
+	}
+
+	c.Status(http.StatusOK)
+	if err := write(); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+func prpKey(c *gin.Context, key, keyID string, pk models.PasswordKey, cipher *models.CipherOptions) (*saes.KeyedBlock, bool) {
+	bc, err := buildCipher(cipher)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return nil, false
+	}
+	kb, _, err := requestKey(bc, key, keyID, pk, 0)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return nil, false
+	}
+	return kb, true
+}
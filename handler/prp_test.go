@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPermutePRPIndices(t *testing.T) {
+	permute := func(size int, indices []int, inverse bool) (int, []int) {
+		t.Helper()
+		parts := make([]string, len(indices))
+		for k, i := range indices {
+			parts[k] = fmt.Sprint(i)
+		}
+		body := fmt.Sprintf(`{"key":"0x2D55","size":%d,"indices":[%s],"inverse":%v}`, size, strings.Join(parts, ","), inverse)
+		code, resp := postJSON(t, PermutePRP, body)
+		data, _ := resp["data"].(map[string]any)
+		raw, _ := data["values"].([]any)
+		values := make([]int, len(raw))
+		for k, v := range raw {
+			values[k] = int(v.(float64))
+		}
+		return code, values
+	}
+
+	// 重复的下标只计算一次，结果按请求顺序给出。
+	if code, got := permute(10, []int{3, 7, 3}, false); code != http.StatusOK || len(got) != 3 || got[0] != got[2] {
+		t.Fatalf("重复下标的结果不符: %d %v", code, got)
+	}
+
+	// 不同下标超过 maxPRPWalks 时查表，结果与逐个游走一致，逆置换还原下标。
+	const size = 300
+	all := make([]int, size)
+	for i := range all {
+		all[i] = i
+	}
+	_, walked := permute(size, all[:maxPRPWalks], false)
+	_, looked := permute(size, all, false)
+	if !reflect.DeepEqual(walked, looked[:maxPRPWalks]) {
+		t.Fatalf("查表得到 %v，逐个游走得到 %v", looked[:maxPRPWalks], walked)
+	}
+	if _, back := permute(size, looked, true); !reflect.DeepEqual(back, all) {
+		t.Fatal("查表的逆置换未还原下标")
+	}
+	if _, back := permute(size, walked, true); !reflect.DeepEqual(back, all[:maxPRPWalks]) {
+		t.Fatal("逐个游走的逆置换未还原下标")
+	}
+
+	if code, _ := permute(2, []int{0, 1, 0}, false); code != http.StatusBadRequest {
+		t.Fatalf("indices 多于 size 应返回 400，得到 %d", code)
+	}
+	if code, _ := permute(10, []int{10}, false); code != http.StatusBadRequest {
+		t.Fatalf("越界下标应返回 400，得到 %d", code)
+	}
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"S-AES/models"
+	"S-AES/utils"
+	"S-AES/utils/codec"
+	"S-AES/utils/drbg"
+	"S-AES/utils/randtest"
+	"S-AES/utils/saes"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxRandomBytes 限制 /random/bytes 与 /random/test 单个来源的字节数。
+	maxRandomBytes = 1 << 20
+	// defaultTestBytes 为统计检验默认的样本大小（10^6 比特，SP 800-22 的建议长度）。
+	defaultTestBytes = 125000
+)
+
+// randomSources 为 /random/test 可选的来源。
+var randomSources = []string{"drbg", "saes-ctr", "crypto/rand", "input"}
+
+var (
+	sharedDRBGOnce sync.Once
+	sharedDRBG     *drbg.DRBG
+	sharedDRBGErr  error
+)
+
+// defaultDRBG 返回进程内共享、由 crypto/rand 播种的生成器。
+func defaultDRBG() (*drbg.DRBG, error) {
+	sharedDRBGOnce.Do(func() {
+		sharedDRBG, sharedDRBGErr = drbg.New(drbg.Config{})
+	})
+	return sharedDRBG, sharedDRBGErr
+}
+
+// RandomBytes 从 S-AES CTR-DRBG 取随机字节，默认十六进制输出，也支持 raw。
+func RandomBytes(c *gin.Context) {
+	var req models.RandomBytesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	if req.Length < 1 || req.Length > maxRandomBytes {
+		respondError(c, http.StatusBadRequest, 1, fmt.Sprintf("length 必须在 1 到 %d 之间", maxRandomBytes))
+		return
+	}
+	outEnc, err := outputEncoding(req.OutputEncoding, codec.Hex, false)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+
+	var d *drbg.DRBG
+	if req.Seed == "" && req.Personalization == "" && req.KeyBits == 0 {
+		d, err = defaultDRBG()
+	} else {
+		d, err = requestDRBG(req.Seed, req.Personalization, req.KeyBits, nil)
+	}
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	out := make([]byte, req.Length)
+	if _, err := d.Read(out); err != nil {
+		respondError(c, http.StatusInternalServerError, 1, err.Error())
+		return
+	}
+	respondCrypt(c, "bytes", "", out, outEnc, gin.H{"drbg": d.Stats()})
+}
+
+// RandomTest 对 DRBG、原始 S-AES 计数器输出、crypto/rand 或给定数据运行 SP 800-22 风格的统计检验，
+// 便于并排比较。
+func RandomTest(c *gin.Context) {
+	var req models.RandomTestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	size := req.Bytes
+	if size == 0 {
+		size = defaultTestBytes
+	}
+	if size < 1 || size > maxRandomBytes {
+		respondError(c, http.StatusBadRequest, 1, fmt.Sprintf("bytes 必须在 1 到 %d 之间", maxRandomBytes))
+		return
+	}
+	bc, err := buildCipher(req.Cipher)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	sources, err := testSources(req.Sources, req.Data != "")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	opts := randtest.Options{
+		Alpha:    req.Alpha,
+		PokerM:   req.PokerM,
+		SerialM:  req.SerialM,
+		EntropyM: req.EntropyM,
+		Lag:      req.Lag,
+	}
+
+	reports := make([]gin.H, 0, len(sources))
+	for _, source := range sources {
+		entry := gin.H{"source": source}
+		var data []byte
+		switch source {
+		case "drbg":
+			d, err := requestDRBG(req.Seed, "", req.KeyBits, bc)
+			if err != nil {
+				respondError(c, http.StatusBadRequest, 1, err.Error())
+				return
+			}
+			data = make([]byte, size)
+			if _, err := d.Read(data); err != nil {
+				respondError(c, http.StatusInternalServerError, 1, err.Error())
+				return
+			}
+		case "saes-ctr":
+			key := req.Key
+			if strings.TrimSpace(key) == "" {
+				var buf [2]byte
+				if _, err := rand.Read(buf[:]); err != nil {
+					respondError(c, http.StatusInternalServerError, 1, err.Error())
+					return
+				}
+				key = utils.FormatHex16(uint16(buf[0])<<8 | uint16(buf[1]))
+			}
+			kb, err := saes.NewKeyedBlock(bc, key)
+			if err != nil {
+				respondError(c, http.StatusBadRequest, 1, err.Error())
+				return
+			}
+			data = counterStream(kb, size)
+			entry["key"] = key
+		case "crypto/rand":
+			data = make([]byte, size)
+			if _, err := rand.Read(data); err != nil {
+				respondError(c, http.StatusInternalServerError, 1, err.Error())
+				return
+			}
+		case "input":
+			if data, err = decodeInput(nil, req.Data, req.InputEncoding, codec.Hex, "data"); err != nil {
+				respondError(c, http.StatusBadRequest, 1, err.Error())
+				return
+			}
+			if len(data) > maxRandomBytes {
+				respondError(c, http.StatusBadRequest, 1, fmt.Sprintf("data 最多 %d 字节", maxRandomBytes))
+				return
+			}
+		}
+		report, err := randtest.Run(data, opts)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, 1, fmt.Sprintf("%s: %v", source, err))
+			return
+		}
+		entry["report"] = report
+		reports = append(reports, entry)
+	}
+	respondSuccess(c, gin.H{"bytes": size, "sources": reports})
+}
+
+// testSources 规范化并去重来源名称；每个来源都要生成至多 maxRandomBytes 字节并运行全部检验，
+// 因此未知名称在生成任何数据之前就报错。未给出时使用 drbg、saes-ctr、crypto/rand，有 data 时另加 input。
+func testSources(names []string, hasData bool) ([]string, error) {
+	if len(names) == 0 {
+		sources := []string{"drbg", "saes-ctr", "crypto/rand"}
+		if hasData {
+			sources = append(sources, "input")
+		}
+		return sources, nil
+	}
+	if len(names) > len(randomSources) {
+		return nil, fmt.Errorf("sources 至多 %d 项", len(randomSources))
+	}
+	sources := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(randomSources, name) {
+			return nil, fmt.Errorf("未知的来源 %q（可选 %s）", name, strings.Join(randomSources, "、"))
+		}
+		if !slices.Contains(sources, name) {
+			sources = append(sources, name)
+		}
+	}
+	return sources, nil
+}
+
+func requestDRBG(seedText, personalization string, keyBits int, bc saes.BlockCipher) (*drbg.DRBG, error) {
+	cfg := drbg.Config{KeyBits: keyBits, Cipher: bc}
+	var err error
+	if seedText != "" {
+		if cfg.Seed, err = parseByteString(seedText); err != nil {
+			return nil, err
+		}
+	}
+	if personalization != "" {
+		if cfg.Personalization, err = parseByteString(personalization); err != nil {
+			return nil, err
+		}
+	}
+	return drbg.New(cfg)
+}
+
+// counterStream 返回同一密钥下 E(0), E(1), … 的原始密文，计数器超过 2^16 后回绕。
+func counterStream(kb *saes.KeyedBlock, size int) []byte {
+	out := make([]byte, size)
+	for i := 0; i < size; i += 2 {
+		block := kb.EncryptBlock(uint16(i / 2))
+		out[i] = byte(block >> 8)
+		if i+1 < size {
+			out[i+1] = byte(block)
+		}
+	}
+	return out
+}
@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRandomTestSources(t *testing.T) {
+	code, resp := postJSON(t, RandomTest, `{"sources":["crypto/rand"," CRYPTO/RAND","crypto/rand"],"bytes":20000}`)
+	data, _ := resp["data"].(map[string]any)
+	if reports, _ := data["sources"].([]any); code != http.StatusOK || len(reports) != 1 {
+		t.Fatalf("重复的来源应只检验一次: %d %v", code, resp)
+	}
+	if code, _ := postJSON(t, RandomTest, `{"sources":["drbg","drbg","drbg","drbg","drbg"]}`); code != http.StatusBadRequest {
+		t.Fatalf("来源过多应返回 400，得到 %d", code)
+	}
+	if code, _ := postJSON(t, RandomTest, `{"sources":["crypto/rand","urandom"]}`); code != http.StatusBadRequest {
+		t.Fatalf("未知来源应返回 400，得到 %d", code)
+	}
+}
@@ -1,11 +1,14 @@
 package handler
 
 import (
+	"crypto/rand"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"S-AES/models"
 	"S-AES/utils"
+	"S-AES/utils/codec"
 	"S-AES/utils/saes"
 
 	"github.com/gin-gonic/gin"
@@ -13,101 +16,269 @@ import (
 
 func Encrypt(c *gin.Context) {
 	var req models.EncryptRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	raw, ok := bindCryptRequest(c, &req)
+	if !ok {
+		return
+	}
+
+	bc, err := buildCipher(req.Cipher)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	kb, stored, err := requestKey(bc, req.Key, req.KeyID, req.PasswordKey, 0)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	outEnc, err := outputEncoding(req.OutputEncoding, blockEncoding, req.Armor)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	plain, err := decodeInput(raw, req.Plaintext, req.InputEncoding, blockEncoding, "明文")
+	if err != nil {
 		respondError(c, http.StatusBadRequest, 1, err.Error())
 		return
 	}
 
-	cipher, err := saes.EncryptBinary(req.Plaintext, req.Key)
+	cipher, err := saes.EncryptBytes(kb, saes.ModeECB, saes.PaddingNone, 0, plain)
 	if err != nil {
 		respondError(c, http.StatusBadRequest, 1, err.Error())
 		return
 	}
 
-	respondSuccess(c, gin.H{"ciphertext": cipher})
+	extra := gin.H{}
+	prefix := storedKeyPrefix(stored, extra)
+	if req.Armor {
+		extra["armored"] = armorCiphertext(kb, saes.ModeECB, saes.PaddingNone, nil, storedKeyRef(stored), cipher)
+	}
+	respondCrypt(c, "ciphertext", prefix, cipher, outEnc, extra)
 }
 
 func Decrypt(c *gin.Context) {
 	var req models.DecryptRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	raw, ok := bindCryptRequest(c, &req)
+	if !ok {
+		return
+	}
+
+	bc, err := buildCipher(req.Cipher)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	outEnc, err := outputEncoding(req.OutputEncoding, blockEncoding, false)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	in, err := resolveCipherInput(bc, raw, req.Ciphertext, req.Key, req.KeyID, req.PasswordKey)
+	if err != nil {
 		respondError(c, http.StatusBadRequest, 1, err.Error())
 		return
 	}
 
-	plain, err := saes.DecryptBinary(req.Ciphertext, req.Key)
+	var plain []byte
+	if in.armored != "" {
+		plain, err = openArmored(in.kb, in.armored)
+	} else {
+		var cipher []byte
+		cipher, err = decodeInput(in.raw, in.text, req.InputEncoding, blockEncoding, "密文")
+		if err == nil {
+			plain, err = saes.DecryptBytes(in.kb, saes.ModeECB, saes.PaddingNone, 0, cipher)
+		}
+	}
 	if err != nil {
 		respondError(c, http.StatusBadRequest, 1, err.Error())
 		return
 	}
 
-	respondSuccess(c, gin.H{"plaintext": plain})
+	respondCrypt(c, "plaintext", "", plain, outEnc, nil)
 }
 
 func EncryptBase64(c *gin.Context) {
 	var req models.EncryptBase64Request
-	if err := c.ShouldBindJSON(&req); err != nil {
+	raw, ok := bindCryptRequest(c, &req)
+	if !ok {
+		return
+	}
+
+	bc, err := buildCipher(req.Cipher)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	kb, stored, err := requestKey(bc, req.Key, req.KeyID, req.PasswordKey, 0)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	outEnc, err := outputEncoding(req.OutputEncoding, codec.Base64, req.Armor)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	plain, err := decodeInput(raw, req.Plaintext, req.InputEncoding, codec.Text, "明文")
+	if err != nil {
 		respondError(c, http.StatusBadRequest, 1, err.Error())
 		return
 	}
 
-	cipher, err := saes.EncryptASCIIToBase64(req.Plaintext, req.Key)
+	cipher, err := saes.EncryptBytes(kb, saes.ModeECB, saes.PaddingZero, 0, plain)
 	if err != nil {
 		respondError(c, http.StatusBadRequest, 1, err.Error())
 		return
 	}
 
-	respondSuccess(c, gin.H{"ciphertext": cipher})
+	extra := gin.H{}
+	prefix := storedKeyPrefix(stored, extra)
+	if req.Armor {
+		extra["armored"] = armorCiphertext(kb, saes.ModeECB, saes.PaddingZero, nil, storedKeyRef(stored), cipher)
+	}
+	respondCrypt(c, "ciphertext", prefix, cipher, outEnc, extra)
 }
 
 func DecryptBase64(c *gin.Context) {
 	var req models.DecryptBase64Request
-	if err := c.ShouldBindJSON(&req); err != nil {
+	raw, ok := bindCryptRequest(c, &req)
+	if !ok {
+		return
+	}
+
+	bc, err := buildCipher(req.Cipher)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	outEnc, err := outputEncoding(req.OutputEncoding, codec.Text, false)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	in, err := resolveCipherInput(bc, raw, req.Ciphertext, req.Key, req.KeyID, req.PasswordKey)
+	if err != nil {
 		respondError(c, http.StatusBadRequest, 1, err.Error())
 		return
 	}
 
-	plain, err := saes.DecryptBase64ToASCII(req.Ciphertext, req.Key)
+	var plain []byte
+	if in.armored != "" {
+		plain, err = openArmored(in.kb, in.armored)
+	} else {
+		var cipher []byte
+		cipher, err = decodeInput(in.raw, in.text, req.InputEncoding, codec.Base64, "密文")
+		if err == nil {
+			plain, err = saes.DecryptBytes(in.kb, saes.ModeECB, saes.PaddingZero, 0, cipher)
+		}
+	}
 	if err != nil {
 		respondError(c, http.StatusBadRequest, 1, err.Error())
 		return
 	}
 
-	respondSuccess(c, gin.H{"plaintext": plain})
+	respondCrypt(c, "plaintext", "", plain, outEnc, nil)
 }
 
 func EncryptCBC(c *gin.Context) {
 	var req models.EncryptCBCRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	raw, ok := bindCryptRequest(c, &req)
+	if !ok {
+		return
+	}
+
+	bc, err := buildCipher(req.Cipher)
+	if err != nil {
 		respondError(c, http.StatusBadRequest, 1, err.Error())
 		return
 	}
+	kb, stored, err := requestKey(bc, req.Key, req.KeyID, req.PasswordKey, 0)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	outEnc, err := outputEncoding(req.OutputEncoding, codec.Base64, req.Armor)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	plain, err := decodeInput(raw, req.Plaintext, req.InputEncoding, codec.Text, "明文")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+
+	var buf [2]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		respondError(c, http.StatusInternalServerError, 1, fmt.Sprintf("生成初始向量失败: %v", err))
+		return
+	}
+	iv := uint16(buf[0])<<8 | uint16(buf[1])
 
-	cipher, iv, err := saes.EncryptASCIIToBase64CBC(req.Plaintext, req.Key)
+	cipher, err := saes.EncryptBytes(kb, saes.ModeCBC, saes.PaddingZero, iv, plain)
 	if err != nil {
 		respondError(c, http.StatusBadRequest, 1, err.Error())
 		return
 	}
 
-	respondSuccess(c, gin.H{
-		"ciphertext": cipher,
-		"iv":         iv,
-	})
+	extra := gin.H{"iv": fmt.Sprintf("0x%04X", iv)}
+	prefix := storedKeyPrefix(stored, extra)
+	if req.Armor {
+		extra["armored"] = armorCiphertext(kb, saes.ModeCBC, saes.PaddingZero, &iv, storedKeyRef(stored), cipher)
+	}
+	respondCrypt(c, "ciphertext", prefix, cipher, outEnc, extra)
 }
 
 func DecryptCBC(c *gin.Context) {
 	var req models.DecryptCBCRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	raw, ok := bindCryptRequest(c, &req)
+	if !ok {
+		return
+	}
+
+	bc, err := buildCipher(req.Cipher)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	outEnc, err := outputEncoding(req.OutputEncoding, codec.Text, false)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	in, err := resolveCipherInput(bc, raw, req.Ciphertext, req.Key, req.KeyID, req.PasswordKey)
+	if err != nil {
 		respondError(c, http.StatusBadRequest, 1, err.Error())
 		return
 	}
 
-	plain, err := saes.DecryptBase64ToASCIICBC(req.Ciphertext, req.Key, req.IV)
+	var plain []byte
+	if in.armored != "" {
+		plain, err = openArmored(in.kb, in.armored)
+	} else {
+		plain, err = decryptCBCInput(in, req.IV, req.InputEncoding)
+	}
 	if err != nil {
 		respondError(c, http.StatusBadRequest, 1, err.Error())
 		return
 	}
 
-	respondSuccess(c, gin.H{"plaintext": plain})
+	respondCrypt(c, "plaintext", "", plain, outEnc, nil)
+}
+
+func decryptCBCInput(in *cipherInput, ivText, encoding string) ([]byte, error) {
+	if strings.TrimSpace(ivText) == "" {
+		return nil, fmt.Errorf("初始向量不能为空")
+	}
+	iv, err := utils.ParseBlockString(ivText)
+	if err != nil {
+		return nil, fmt.Errorf("无法解析初始向量: %w", err)
+	}
+	cipher, err := decodeInput(in.raw, in.text, encoding, codec.Base64, "密文")
+	if err != nil {
+		return nil, err
+	}
+	return saes.DecryptBytes(in.kb, saes.ModeCBC, saes.PaddingZero, iv, cipher)
 }
 
 func MeetInTheMiddleAttack(c *gin.Context) {
@@ -116,27 +287,24 @@ func MeetInTheMiddleAttack(c *gin.Context) {
 		respondError(c, http.StatusBadRequest, 1, err.Error())
 		return
 	}
+
+	bc, err := buildCipher(req.Cipher)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
 	if len(req.Pairs) == 0 {
 		respondError(c, http.StatusBadRequest, 1, "至少需要提供一组明文与密文")
 		return
 	}
 
-	pairs := make([]utils.PlainCipherPair, 0, len(req.Pairs))
-	for idx, pair := range req.Pairs {
-		plain, err := utils.ParseBlockString(pair.Plaintext)
-		if err != nil {
-			respondError(c, http.StatusBadRequest, 1, fmt.Sprintf("第 %d 组明文解析失败: %v", idx+1, err))
-			return
-		}
-		cipher, err := utils.ParseBlockString(pair.Ciphertext)
-		if err != nil {
-			respondError(c, http.StatusBadRequest, 1, fmt.Sprintf("第 %d 组密文解析失败: %v", idx+1, err))
-			return
-		}
-		pairs = append(pairs, utils.PlainCipherPair{Plain: plain, Cipher: cipher})
+	pairs, err := parseAttackPairs(req.Pairs, req.InputEncoding)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
 	}
 
-	keys, err := utils.MeetInTheMiddleAttack(pairs)
+	keys, err := utils.MeetInTheMiddleAttack(bc, pairs)
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, 1, err.Error())
 		return
@@ -160,6 +328,84 @@ func MeetInTheMiddleAttack(c *gin.Context) {
 	})
 }
 
+func BruteForceAttack(c *gin.Context) {
+	var req models.BruteForceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+
+	bc, err := buildCipher(req.Cipher)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	if len(req.Pairs) == 0 {
+		respondError(c, http.StatusBadRequest, 1, "至少需要提供一组明文与密文")
+		return
+	}
+
+	pairs, err := parseAttackPairs(req.Pairs, req.InputEncoding)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+
+	keys, err := utils.BruteForceAttack(bc, pairs)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, 1, err.Error())
+		return
+	}
+
+	respKeys := make([]models.BruteForceKey, 0, len(keys))
+	for _, key := range keys {
+		respKeys = append(respKeys, models.BruteForceKey{
+			Hex: utils.FormatHex16(key),
+			Bin: utils.FormatBinary16(key),
+		})
+	}
+
+	respondSuccess(c, models.BruteForceResponse{
+		Count: len(respKeys),
+		Keys:  respKeys,
+	})
+}
+
+// parseAttackPairs 解析已知明密文对；未指定编码时沿用位串或 0x 十六进制格式。
+func parseAttackPairs(input []models.AttackPair, encoding string) ([]utils.PlainCipherPair, error) {
+	enc, err := codec.Parse(encoding, blockEncoding)
+	if err != nil {
+		return nil, err
+	}
+	pairs := make([]utils.PlainCipherPair, 0, len(input))
+	for idx, pair := range input {
+		plain, err := parsePairBlock(enc, pair.Plaintext)
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 组明文解析失败: %v", idx+1, err)
+		}
+		cipher, err := parsePairBlock(enc, pair.Ciphertext)
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 组密文解析失败: %v", idx+1, err)
+		}
+		pairs = append(pairs, utils.PlainCipherPair{Plain: plain, Cipher: cipher})
+	}
+	return pairs, nil
+}
+
+func parsePairBlock(enc codec.Encoding, s string) (uint16, error) {
+	if enc == blockEncoding {
+		return utils.ParseBlockString(s)
+	}
+	data, err := codec.Decode(enc, s)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) != 2 {
+		return 0, fmt.Errorf("必须恰好是 16 位分组，实际为 %d 字节", len(data))
+	}
+	return uint16(data[0])<<8 | uint16(data[1]), nil
+}
+
 func respondSuccess(c *gin.Context, data interface{}) {
 	c.JSON(http.StatusOK, models.APIResponse{
 		Code:    0,
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"S-AES/models"
+	"S-AES/utils"
+	"S-AES/utils/saes"
+	"S-AES/utils/sca"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxJSONTraces 限制以 JSON 直接返回的曲线条数，更多曲线请使用下载接口。
+const maxJSONTraces = 5000
+
+func GeneratePowerTraces(c *gin.Context) {
+	_, cipher, cfg, ok := bindPowerTraceRequest(c)
+	if !ok {
+		return
+	}
+	if cfg.Count > maxJSONTraces {
+		respondError(c, http.StatusBadRequest, 1, fmt.Sprintf("JSON 最多返回 %d 条曲线，请使用 /sca/traces/download", maxJSONTraces))
+		return
+	}
+
+	set, err := sca.Generate(cipher, cfg)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+
+	resp := models.PowerTraceResponse{
+		Count:       len(set.Traces),
+		Model:       string(cfg.Model),
+		Points:      make([]string, 0, len(set.Points)),
+		Plaintexts:  make([]string, 0, len(set.Plaintexts)),
+		Ciphertexts: make([]string, 0, len(set.Ciphertexts)),
+		Traces:      set.Traces,
+	}
+	for _, p := range set.Points {
+		resp.Points = append(resp.Points, p.String())
+	}
+	for i := range set.Plaintexts {
+		resp.Plaintexts = append(resp.Plaintexts, utils.FormatHex16(set.Plaintexts[i]))
+		resp.Ciphertexts = append(resp.Ciphertexts, utils.FormatHex16(set.Ciphertexts[i]))
+	}
+	respondSuccess(c, resp)
+}
+
+// DownloadPowerTraces 按相同参数（含种子）重新生成曲线并以 CSV、NPY 或 NPZ 文件返回。
+func DownloadPowerTraces(c *gin.Context) {
+	req, cipher, cfg, ok := bindPowerTraceRequest(c)
+	if !ok {
+		return
+	}
+	format := strings.ToLower(strings.TrimSpace(req.Format))
+
+	set, err := sca.Generate(cipher, cfg)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+
+	var write func() error
+	switch format {
+	case "", "csv":
+		c.Header("Content-Type", "text/csv")
+		c.Header("Content-Disposition", `attachment; filename="traces.csv"`)
+		write = func() error { return sca.WriteCSV(c.Writer, set) }
+	case "npy":
+		c.Header("Content-Type", "application/octet-stream")
+		c.Header("Content-Disposition", `attachment; filename="traces.npy"`)
+		write = func() error { return sca.WriteNPY(c.Writer, set) }
+	case "npz":
+		c.Header("Content-Type", "application/zip")
+		c.Header("Content-Disposition", `attachment; filename="traces.npz"`)
+		write = func() error { return sca.WriteNPZ(c.Writer, set) }
+	default:
+		respondError(c, http.StatusBadRequest, 1, "不支持的格式（可选 csv、npy、npz）")
+		return
+	}
+
+	c.Status(http.StatusOK)
+	if err := write(); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+func CorrelationPowerAnalysis(c *gin.Context) {
+	_, cipher, cfg, ok := bindPowerTraceRequest(c)
+	if !ok {
+		return
+	}
+
+	set, err := sca.Generate(cipher, cfg)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+	result, err := sca.CPA(cipher, set, cfg.Model, cfg.Key)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return
+	}
+
+	resp := models.CPAResponse{
+		Traces:          result.Traces,
+		Model:           string(result.Model),
+		RecoveredKey:    utils.FormatHex16(result.Recovered),
+		CorrectKey:      utils.FormatHex16(cfg.Key),
+		Success:         result.Recovered == cfg.Key,
+		TracesToSuccess: result.TracesToSuccess,
+	}
+	for _, nr := range result.Nibbles {
+		resp.Nibbles = append(resp.Nibbles, models.CPANibble{
+			Nibble:      nr.Nibble,
+			Best:        fmt.Sprintf("0x%X", nr.Best),
+			Correct:     fmt.Sprintf("0x%X", nr.Correct),
+			Rank:        nr.Rank,
+			Correlation: nr.Correlation,
+			Scores:      nr.Scores,
+		})
+	}
+	for _, rp := range result.Ranks {
+		resp.Ranks = append(resp.Ranks, models.CPARankPoint{Traces: rp.Traces, Ranks: rp.Ranks})
+	}
+	respondSuccess(c, resp)
+}
+
+func bindPowerTraceRequest(c *gin.Context) (req models.PowerTraceRequest, cipher *saes.Cipher, cfg sca.Config, ok bool) {
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return req, nil, cfg, false
+	}
+
+	cipher, err := buildGenericCipher(req.Cipher)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return req, nil, cfg, false
+	}
+	key, err := utils.ParseBlockString(req.Key)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, "无法解析密钥: "+err.Error())
+		return req, nil, cfg, false
+	}
+	model, err := sca.ParseModel(req.Model)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, 1, err.Error())
+		return req, nil, cfg, false
+	}
+
+	cfg = sca.Config{Key: key, Count: req.Count, Noise: 1.0, Model: model, Seed: req.Seed}
+	if cfg.Count == 0 {
+		cfg.Count = 500
+	}
+	if req.Noise != nil {
+		cfg.Noise = *req.Noise
+	}
+	return req, cipher, cfg, true
+}
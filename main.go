@@ -1,13 +1,25 @@
 package main
 
 import (
+	"S-AES/handler"
 	"S-AES/router"
+	"S-AES/utils/keystore"
 	"log"
+	"os"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	// 设置 SAES_KEYSTORE 时密钥库加密保存在该文件中，口令取自 SAES_KEYSTORE_PASSPHRASE；否则仅驻留内存。
+	if path := os.Getenv("SAES_KEYSTORE"); path != "" {
+		store, err := keystore.Open(path, []byte(os.Getenv("SAES_KEYSTORE_PASSPHRASE")))
+		if err != nil {
+			log.Fatalf("打开密钥库失败: %v", err)
+		}
+		handler.SetKeyStore(store)
+	}
+
 	r := gin.Default()
 	router.InitRouter(r)
 	if err := r.Run("localhost:8080"); err != nil {
@@ -1,34 +1,92 @@
 package models
 
+import "encoding/json"
+
+// CipherOptions 选择 S-AES 变体与分组实现。JSON 请求放在 cipher 对象中，
+// application/octet-stream 请求则以同名查询参数给出。
+type CipherOptions struct {
+	SBox            string `json:"sbox" form:"sbox"`
+	Poly            string `json:"poly" form:"poly"`
+	MixColumns      string `json:"mix_columns" form:"mix_columns"`
+	Rounds          int    `json:"rounds" form:"rounds"`
+	FinalMixColumns bool   `json:"final_mix_columns" form:"final_mix_columns"`
+	Engine          string `json:"engine" form:"engine"`
+}
+
+// PasswordKey 是 key 的替代：由口令与盐经 KDF 派生密钥，二者只能提供其一。
+// KDF 为 pbkdf2（默认）、scrypt 或 argon2id，使用默认参数；KeyBits 默认为 16。
+type PasswordKey struct {
+	Password string `json:"password" form:"password"`
+	Salt     string `json:"salt" form:"salt"`
+	KDF      string `json:"kdf" form:"kdf"`
+	KeyBits  int    `json:"key_bits" form:"key_bits"`
+}
+
+// 加解密请求的密钥可由 key、口令或密钥库中的 key_id 三者之一给出。
+// 加解密请求中的明文/密文按 input_encoding 解码、结果按 output_encoding 编码；
+// 以 application/octet-stream 提交时请求体即为输入，其余字段取自查询参数。
 type EncryptRequest struct {
-	Plaintext string `json:"plaintext" binding:"required"`
-	Key       string `json:"key" binding:"required"`
+	Plaintext string `json:"plaintext" form:"plaintext"`
+	Key       string `json:"key" form:"key"`
+	KeyID     string `json:"key_id" form:"key_id"`
+	PasswordKey
+	Armor          bool           `json:"armor" form:"armor"`
+	InputEncoding  string         `json:"input_encoding" form:"input_encoding"`
+	OutputEncoding string         `json:"output_encoding" form:"output_encoding"`
+	Cipher         *CipherOptions `json:"cipher"`
 }
 
 type EncryptBase64Request struct {
-	Plaintext string `json:"plaintext" binding:"required"`
-	Key       string `json:"key" binding:"required"`
+	Plaintext string `json:"plaintext" form:"plaintext"`
+	Key       string `json:"key" form:"key"`
+	KeyID     string `json:"key_id" form:"key_id"`
+	PasswordKey
+	Armor          bool           `json:"armor" form:"armor"`
+	InputEncoding  string         `json:"input_encoding" form:"input_encoding"`
+	OutputEncoding string         `json:"output_encoding" form:"output_encoding"`
+	Cipher         *CipherOptions `json:"cipher"`
 }
 
 type DecryptRequest struct {
-	Ciphertext string `json:"ciphertext" binding:"required"`
-	Key        string `json:"key" binding:"required"`
+	Ciphertext string `json:"ciphertext" form:"ciphertext"`
+	Key        string `json:"key" form:"key"`
+	KeyID      string `json:"key_id" form:"key_id"`
+	PasswordKey
+	InputEncoding  string         `json:"input_encoding" form:"input_encoding"`
+	OutputEncoding string         `json:"output_encoding" form:"output_encoding"`
+	Cipher         *CipherOptions `json:"cipher"`
 }
 
 type DecryptBase64Request struct {
-	Ciphertext string `json:"ciphertext" binding:"required"`
-	Key        string `json:"key" binding:"required"`
+	Ciphertext string `json:"ciphertext" form:"ciphertext"`
+	Key        string `json:"key" form:"key"`
+	KeyID      string `json:"key_id" form:"key_id"`
+	PasswordKey
+	InputEncoding  string         `json:"input_encoding" form:"input_encoding"`
+	OutputEncoding string         `json:"output_encoding" form:"output_encoding"`
+	Cipher         *CipherOptions `json:"cipher"`
 }
 
 type EncryptCBCRequest struct {
-	Plaintext string `json:"plaintext" binding:"required"`
-	Key       string `json:"key" binding:"required"`
+	Plaintext string `json:"plaintext" form:"plaintext"`
+	Key       string `json:"key" form:"key"`
+	KeyID     string `json:"key_id" form:"key_id"`
+	PasswordKey
+	Armor          bool           `json:"armor" form:"armor"`
+	InputEncoding  string         `json:"input_encoding" form:"input_encoding"`
+	OutputEncoding string         `json:"output_encoding" form:"output_encoding"`
+	Cipher         *CipherOptions `json:"cipher"`
 }
 
 type DecryptCBCRequest struct {
-	Ciphertext string `json:"ciphertext" binding:"required"`
-	Key        string `json:"key" binding:"required"`
-	IV         string `json:"iv" binding:"required"`
+	Ciphertext string `json:"ciphertext" form:"ciphertext"`
+	Key        string `json:"key" form:"key"`
+	KeyID      string `json:"key_id" form:"key_id"`
+	PasswordKey
+	IV             string         `json:"iv" form:"iv"`
+	InputEncoding  string         `json:"input_encoding" form:"input_encoding"`
+	OutputEncoding string         `json:"output_encoding" form:"output_encoding"`
+	Cipher         *CipherOptions `json:"cipher"`
 }
 
 type AttackPair struct {
@@ -37,5 +95,371 @@ type AttackPair struct {
 }
 
 type MeetInTheMiddleRequest struct {
-	Pairs []AttackPair `json:"pairs" binding:"required"`
+	Pairs         []AttackPair   `json:"pairs" binding:"required"`
+	InputEncoding string         `json:"input_encoding"`
+	Cipher        *CipherOptions `json:"cipher"`
+}
+
+type BruteForceRequest struct {
+	Pairs         []AttackPair   `json:"pairs" binding:"required"`
+	InputEncoding string         `json:"input_encoding"`
+	Cipher        *CipherOptions `json:"cipher"`
+}
+
+type SBoxAnalysisRequest struct {
+	SBox       string `json:"sbox" binding:"required"`
+	Poly       string `json:"poly"`
+	MixColumns string `json:"mix_columns"`
+}
+
+type TimingAnalysisRequest struct {
+	Measurements   int            `json:"measurements"`
+	Repeat         int            `json:"repeat"`
+	Key            string         `json:"key"`
+	FixedPlaintext string         `json:"fixed_plaintext"`
+	Seed           uint64         `json:"seed"`
+	Cipher         *CipherOptions `json:"cipher"`
+}
+
+type PowerTraceRequest struct {
+	Key    string         `json:"key" binding:"required"`
+	Count  int            `json:"count"`
+	Noise  *float64       `json:"noise"`
+	Model  string         `json:"model"`
+	Seed   uint64         `json:"seed"`
+	Format string         `json:"format"`
+	Cipher *CipherOptions `json:"cipher"`
+}
+
+type FaultLocation struct {
+	Round int    `json:"round"`
+	Step  string `json:"step" binding:"required"`
+}
+
+type FaultRequest struct {
+	Key      string         `json:"key" binding:"required"`
+	Kind     string         `json:"kind"`
+	Location *FaultLocation `json:"location"`
+	Nibble   *int           `json:"nibble"`
+	Bit      *int           `json:"bit"`
+	Count    int            `json:"count"`
+	Seed     uint64         `json:"seed"`
+	Cipher   *CipherOptions `json:"cipher"`
+}
+
+type AlgebraExportRequest struct {
+	Pairs         []AttackPair   `json:"pairs" binding:"required"`
+	Format        string         `json:"format"`
+	InputEncoding string         `json:"input_encoding"`
+	Cipher        *CipherOptions `json:"cipher"`
+}
+
+type AlgebraSolveRequest struct {
+	Pairs         []AttackPair   `json:"pairs" binding:"required"`
+	Limit         int            `json:"limit"`
+	MaxConflicts  int            `json:"max_conflicts"`
+	InputEncoding string         `json:"input_encoding"`
+	Cipher        *CipherOptions `json:"cipher"`
+}
+
+// FileEncryptRequest 与 FileDecryptRequest 为 multipart 表单字段，文件本身位于 file 字段。
+// 未提供 key 与 password 时，加密使用密钥库中名为 key_id 的当前版本，
+// 解密按容器记录的 Key-ID 查找密钥库；否则 key_id 只是写入容器的标签。
+type FileEncryptRequest struct {
+	Key string `form:"key"`
+	PasswordKey
+	Mode    string `form:"mode"`
+	Padding string `form:"padding"`
+	IV      string `form:"iv"`
+	KeyID   string `form:"key_id"`
+	MACKey  string `form:"mac_key"`
+	Engine  string `form:"engine"`
+}
+
+type FileDecryptRequest struct {
+	Key string `form:"key"`
+	PasswordKey
+	MACKey string `form:"mac_key"`
+	Engine string `form:"engine"`
+}
+
+// KDFDeriveRequest 中 Salt 为空时生成随机盐；各参数为 0 时取所选 KDF 的默认值。
+type KDFDeriveRequest struct {
+	Password    string         `json:"password" binding:"required"`
+	Salt        string         `json:"salt"`
+	Algorithm   string         `json:"algorithm"`
+	KeyBits     int            `json:"key_bits"`
+	Iterations  int            `json:"iterations"`
+	N           int            `json:"n"`
+	R           int            `json:"r"`
+	Memory      uint32         `json:"memory"`
+	Parallelism int            `json:"parallelism"`
+	Crack       bool           `json:"crack"`
+	CrackPairs  int            `json:"crack_pairs"`
+	Cipher      *CipherOptions `json:"cipher"`
+}
+
+type KeyCreateRequest struct {
+	Name string `json:"name" binding:"required"`
+	Bits int    `json:"bits"`
+}
+
+type KeyRotateRequest struct {
+	Bits int `json:"bits"`
+}
+
+// RewrapRequest 把旧版本密钥加密的密文改用 key_id 的当前版本重新加密。
+// 每条密文须带 "saes:vN:" 前缀，或为 Key-ID 记录了版本的 ASCII 封装；
+// Mode 默认 ecb，OutputEncoding 默认与 InputEncoding（默认 base64）相同。
+type RewrapRequest struct {
+	KeyID          string         `json:"key_id" binding:"required"`
+	Ciphertexts    []string       `json:"ciphertexts" binding:"required"`
+	Mode           string         `json:"mode"`
+	IV             string         `json:"iv"`
+	InputEncoding  string         `json:"input_encoding"`
+	OutputEncoding string         `json:"output_encoding"`
+	Cipher         *CipherOptions `json:"cipher"`
+}
+
+// KeyWrapRequest 中 key、key_id 或口令给出密钥加密密钥（KEK）；待包装的数据由 plaintext 给出，
+// 或用 wrap_key_id 引用密钥库中的密钥（name 或 name:vN）。Padded 为 true 时使用 KWP。
+type KeyWrapRequest struct {
+	Plaintext string `json:"plaintext" form:"plaintext"`
+	WrapKeyID string `json:"wrap_key_id" form:"wrap_key_id"`
+	Key       string `json:"key" form:"key"`
+	KeyID     string `json:"key_id" form:"key_id"`
+	PasswordKey
+	Padded         bool           `json:"padded" form:"padded"`
+	InputEncoding  string         `json:"input_encoding" form:"input_encoding"`
+	OutputEncoding string         `json:"output_encoding" form:"output_encoding"`
+	Cipher         *CipherOptions `json:"cipher"`
+}
+
+type KeyUnwrapRequest struct {
+	Ciphertext string `json:"ciphertext" form:"ciphertext"`
+	Key        string `json:"key" form:"key"`
+	KeyID      string `json:"key_id" form:"key_id"`
+	PasswordKey
+	Padded         bool           `json:"padded" form:"padded"`
+	InputEncoding  string         `json:"input_encoding" form:"input_encoding"`
+	OutputEncoding string         `json:"output_encoding" form:"output_encoding"`
+	Cipher         *CipherOptions `json:"cipher"`
+}
+
+// DiskEncryptRequest 与 DiskDecryptRequest 以 XTS 模式按扇区加解密模拟的磁盘镜像，
+// 第 i 个扇区的数据单元号为 first_sector+i。tweak_key 为空时 key 须为 32 位，
+// 高低 16 位分别作数据密钥与调整密钥。sectors 非空时只处理其中列出的扇区（取绝对扇区号）。
+type DiskEncryptRequest struct {
+	Plaintext string `json:"plaintext" form:"plaintext"`
+	Key       string `json:"key" form:"key"`
+	KeyID     string `json:"key_id" form:"key_id"`
+	PasswordKey
+	TweakKey       string         `json:"tweak_key" form:"tweak_key"`
+	SectorSize     int            `json:"sector_size" form:"sector_size"`
+	FirstSector    uint64         `json:"first_sector" form:"first_sector"`
+	Sectors        []uint64       `json:"sectors" form:"sectors"`
+	InputEncoding  string         `json:"input_encoding" form:"input_encoding"`
+	OutputEncoding string         `json:"output_encoding" form:"output_encoding"`
+	Cipher         *CipherOptions `json:"cipher"`
+}
+
+type DiskDecryptRequest struct {
+	Ciphertext string `json:"ciphertext" form:"ciphertext"`
+	Key        string `json:"key" form:"key"`
+	KeyID      string `json:"key_id" form:"key_id"`
+	PasswordKey
+	TweakKey       string         `json:"tweak_key" form:"tweak_key"`
+	SectorSize     int            `json:"sector_size" form:"sector_size"`
+	FirstSector    uint64         `json:"first_sector" form:"first_sector"`
+	Sectors        []uint64       `json:"sectors" form:"sectors"`
+	InputEncoding  string         `json:"input_encoding" form:"input_encoding"`
+	OutputEncoding string         `json:"output_encoding" form:"output_encoding"`
+	Cipher         *CipherOptions `json:"cipher"`
+}
+
+// FPEOptions 描述保留格式加密的定义域。Domain 大于 0 时输入为 [0, domain) 内的十进制整数，
+// 按循环游走加密；否则输入为字母表（或基数对应的默认字母表）上的数字串。
+// Tweak 以 0x 开头时按十六进制解码，否则取 UTF-8 字节。
+type FPEOptions struct {
+	Scheme   string `json:"scheme"`
+	Radix    int    `json:"radix"`
+	Alphabet string `json:"alphabet"`
+	Tweak    string `json:"tweak"`
+	Domain   int    `json:"domain"`
+}
+
+type FPEEncryptRequest struct {
+	Plaintext string `json:"plaintext" binding:"required"`
+	Key       string `json:"key"`
+	KeyID     string `json:"key_id"`
+	PasswordKey
+	FPEOptions
+	Cipher *CipherOptions `json:"cipher"`
+}
+
+// FPEDecryptRequest 的密文不携带密钥版本，使用密钥库时应以 key_id 指定 name:vN。
+type FPEDecryptRequest struct {
+	Ciphertext string `json:"ciphertext" binding:"required"`
+	Key        string `json:"key"`
+	KeyID      string `json:"key_id"`
+	PasswordKey
+	FPEOptions
+	Cipher *CipherOptions `json:"cipher"`
+}
+
+// PRPPermuteRequest 对 [0, size) 中的各下标求 π(i)，Inverse 为 true 时求 π⁻¹(i)。
+type PRPPermuteRequest struct {
+	Key   string `json:"key"`
+	KeyID string `json:"key_id"`
+	PasswordKey
+	Size    int            `json:"size" binding:"required"`
+	Indices []int          `json:"indices" binding:"required"`
+	Inverse bool           `json:"inverse"`
+	Cipher  *CipherOptions `json:"cipher"`
+}
+
+// PRPShuffleRequest 按密钥确定性地重排 items（任意 JSON 值），Inverse 为 true 时恢复原顺序。
+type PRPShuffleRequest struct {
+	Key   string `json:"key"`
+	KeyID string `json:"key_id"`
+	PasswordKey
+	Items   []json.RawMessage `json:"items" binding:"required"`
+	Inverse bool              `json:"inverse"`
+	Cipher  *CipherOptions    `json:"cipher"`
+}
+
+// PRPTableRequest 为 GET /prp/permutation 的查询参数。Format 为 json（默认，分页）、csv 或 jsonl，
+// 后两者流式输出，Limit 为 0 时一直输出到表尾。
+type PRPTableRequest struct {
+	Key   string `form:"key"`
+	KeyID string `form:"key_id"`
+	PasswordKey
+	Size   int    `form:"size" binding:"required"`
+	Offset int    `form:"offset"`
+	Limit  int    `form:"limit"`
+	Format string `form:"format"`
+	Engine string `form:"engine"`
+}
+
+// RandomBytesRequest 为 GET /random/bytes 的查询参数。Seed 为空时从全局实例（crypto/rand 播种）取字节，
+// 否则以 Seed 新建可复现的实例；Seed 与 Personalization 以 0x 开头时按十六进制解析，否则取 UTF-8 字节。
+type RandomBytesRequest struct {
+	Length          int    `form:"length" binding:"required"`
+	OutputEncoding  string `form:"output_encoding"`
+	Seed            string `form:"seed"`
+	Personalization string `form:"personalization"`
+	KeyBits         int    `form:"key_bits"`
+}
+
+// RandomTestRequest 对若干来源各取 Bytes 字节运行统计检验。Sources 可选 drbg、saes-ctr（同一密钥下
+// 连续计数器的原始密文）、crypto/rand 与 input（Data 按 InputEncoding 解码，默认十六进制）。
+type RandomTestRequest struct {
+	Sources       []string       `json:"sources"`
+	Bytes         int            `json:"bytes"`
+	Seed          string         `json:"seed"`
+	KeyBits       int            `json:"key_bits"`
+	Key           string         `json:"key"`
+	Data          string         `json:"data"`
+	InputEncoding string         `json:"input_encoding"`
+	Alpha         float64        `json:"alpha"`
+	PokerM        int            `json:"poker_m"`
+	SerialM       int            `json:"serial_m"`
+	EntropyM      int            `json:"entropy_m"`
+	Lag           int            `json:"lag"`
+	Cipher        *CipherOptions `json:"cipher"`
+}
+
+// HashRequest 中 Construction 为 dm（默认）、mmo 或 mp，Bits 为 16（默认）或 32；
+// 消息默认按 UTF-8 文本解释。
+type HashRequest struct {
+	Message        string         `json:"message" form:"message"`
+	Construction   string         `json:"construction" form:"construction"`
+	Bits           int            `json:"bits" form:"bits"`
+	InputEncoding  string         `json:"input_encoding" form:"input_encoding"`
+	OutputEncoding string         `json:"output_encoding" form:"output_encoding"`
+	Cipher         *CipherOptions `json:"cipher"`
+}
+
+// HashCollisionRequest 以 prefix 后接 4 字节计数器的消息做生日攻击。
+type HashCollisionRequest struct {
+	Prefix       string         `json:"prefix"`
+	Construction string         `json:"construction"`
+	Bits         int            `json:"bits"`
+	MaxAttempts  int            `json:"max_attempts"`
+	Cipher       *CipherOptions `json:"cipher"`
+}
+
+// HashPreimageRequest 寻找与 Message 摘要相同的另一条等长消息。
+type HashPreimageRequest struct {
+	Message       string         `json:"message" binding:"required"`
+	InputEncoding string         `json:"input_encoding"`
+	Construction  string         `json:"construction"`
+	Bits          int            `json:"bits"`
+	MaxAttempts   int            `json:"max_attempts"`
+	Cipher        *CipherOptions `json:"cipher"`
+}
+
+// CiphertextAnalysisRequest 统计密文的分组重复与频率分布，Ciphertext 默认按十六进制解码；
+// Top 为返回的高频分组个数，默认 10。
+type CiphertextAnalysisRequest struct {
+	Ciphertext    string `json:"ciphertext" form:"ciphertext"`
+	InputEncoding string `json:"input_encoding" form:"input_encoding"`
+	Top           int    `json:"top" form:"top"`
+}
+
+// PenguinDemoRequest 为 GET /analysis/penguin 的查询参数。Width 默认 256；IV 为 CBC 的初始向量，
+// 为空时随机生成；Format 为 png（默认，原图、ECB、CBC 左右拼接）或 json（两种密文像素的统计）。
+type PenguinDemoRequest struct {
+	Key   string `form:"key"`
+	KeyID string `form:"key_id"`
+	PasswordKey
+	IV     string `form:"iv"`
+	Width  int    `form:"width"`
+	Format string `form:"format"`
+	Engine string `form:"engine"`
+}
+
+// ImageEncryptRequest 为 multipart 表单字段，图像位于 file 字段。Mode 默认 cbc；
+// IV 为空且模式需要初始向量时随机生成。
+type ImageEncryptRequest struct {
+	Key   string `form:"key"`
+	KeyID string `form:"key_id"`
+	PasswordKey
+	Mode string `form:"mode"`
+	IV   string `form:"iv"`
+}
+
+// BatchRequest 在一次请求中执行多个操作，Concurrency 为并发执行的上限，默认等于 CPU 数。
+// Cipher 为全部操作共用的 S-AES 变体。
+type BatchRequest struct {
+	Operations  []BatchOperation `json:"operations" binding:"required"`
+	Concurrency int              `json:"concurrency"`
+	Cipher      *CipherOptions   `json:"cipher"`
+}
+
+// BatchOperation 为单个操作。Op 为 encrypt、decrypt 或 trace；Mode 默认 ecb，Padding 默认 none，
+// Data 的输入输出编码默认与 /encrypt 相同（16 位二进制分组）。trace 只接受 16 位 key 与单个分组，
+// 返回轮密钥与每一步之后的状态。ID 原样回显，便于对应结果。
+type BatchOperation struct {
+	ID    json.RawMessage `json:"id"`
+	Op    string          `json:"op"`
+	Mode  string          `json:"mode"`
+	Key   string          `json:"key"`
+	KeyID string          `json:"key_id"`
+	PasswordKey
+	Padding        string `json:"padding"`
+	IV             string `json:"iv"`
+	Data           string `json:"data"`
+	InputEncoding  string `json:"input_encoding"`
+	OutputEncoding string `json:"output_encoding"`
+}
+
+// CodebookRequest 为 GET /codebook 的查询参数。Format 为 json（默认，只返回置换结构统计）、
+// csv、jsonl 或 bin（完整对照表，统计摘要写入响应头）。
+type CodebookRequest struct {
+	Key   string `form:"key"`
+	KeyID string `form:"key_id"`
+	PasswordKey
+	Format string `form:"format"`
+	Engine string `form:"engine"`
 }
@@ -1,5 +1,7 @@
 package models
 
+import "encoding/json"
+
 type APIResponse struct {
 	Code    int         `json:"code"`
 	Message string      `json:"message"`
@@ -19,3 +21,225 @@ type MeetInTheMiddleResponse struct {
 	Count int                  `json:"count"`
 	Keys  []MeetInTheMiddleKey `json:"keys"`
 }
+
+type BruteForceKey struct {
+	Hex string `json:"hex"`
+	Bin string `json:"bin"`
+}
+
+type BruteForceResponse struct {
+	Count int             `json:"count"`
+	Keys  []BruteForceKey `json:"keys"`
+}
+
+type SBoxAnalysisResponse struct {
+	SBox                   string      `json:"sbox"`
+	Bijective              bool        `json:"bijective"`
+	InverseSBox            string      `json:"inverse_sbox,omitempty"`
+	DifferentialUniformity int         `json:"differential_uniformity"`
+	DDT                    [16][16]int `json:"ddt"`
+	Nonlinearity           int         `json:"nonlinearity"`
+	LAT                    [16][16]int `json:"lat"`
+	AlgebraicDegree        int         `json:"algebraic_degree"`
+	CoordinateANF          [4]string   `json:"coordinate_anf"`
+	FixedPoints            []int       `json:"fixed_points"`
+	Poly                   string      `json:"poly"`
+	PolyIrreducible        bool        `json:"poly_irreducible"`
+	MixColumns             string      `json:"mix_columns"`
+	BranchNumber           int         `json:"branch_number,omitempty"`
+	UsableAsCipher         bool        `json:"usable_as_cipher"`
+	CipherError            string      `json:"cipher_error,omitempty"`
+}
+
+type TimingCrop struct {
+	Percentile float64 `json:"percentile"`
+	T          float64 `json:"t"`
+}
+
+type TimingResult struct {
+	Engine       string       `json:"engine"`
+	Measurements int          `json:"measurements"`
+	FixedCount   int          `json:"fixed_count"`
+	RandomCount  int          `json:"random_count"`
+	FixedMeanNs  float64      `json:"fixed_mean_ns"`
+	RandomMeanNs float64      `json:"random_mean_ns"`
+	T            float64      `json:"t"`
+	Crops        []TimingCrop `json:"crops"`
+	MaxAbsT      float64      `json:"max_abs_t"`
+	Leaky        bool         `json:"leaky"`
+}
+
+type TimingAnalysisResponse struct {
+	Threshold float64        `json:"threshold"`
+	Results   []TimingResult `json:"results"`
+}
+
+type PowerTraceResponse struct {
+	Count       int         `json:"count"`
+	Model       string      `json:"model"`
+	Points      []string    `json:"points"`
+	Plaintexts  []string    `json:"plaintexts"`
+	Ciphertexts []string    `json:"ciphertexts"`
+	Traces      [][]float64 `json:"traces"`
+}
+
+type CPANibble struct {
+	Nibble      int         `json:"nibble"`
+	Best        string      `json:"best"`
+	Correct     string      `json:"correct"`
+	Rank        int         `json:"rank"`
+	Correlation float64     `json:"correlation"`
+	Scores      [16]float64 `json:"scores"`
+}
+
+type CPARankPoint struct {
+	Traces int    `json:"traces"`
+	Ranks  [4]int `json:"ranks"`
+}
+
+type CPAResponse struct {
+	Traces          int            `json:"traces"`
+	Model           string         `json:"model"`
+	RecoveredKey    string         `json:"recovered_key"`
+	CorrectKey      string         `json:"correct_key"`
+	Success         bool           `json:"success"`
+	TracesToSuccess int            `json:"traces_to_success"`
+	Nibbles         []CPANibble    `json:"nibbles"`
+	Ranks           []CPARankPoint `json:"ranks"`
+}
+
+type FaultPair struct {
+	Plaintext  string `json:"plaintext"`
+	Correct    string `json:"correct"`
+	Faulty     string `json:"faulty"`
+	Mask       string `json:"mask"`
+	Difference string `json:"difference"`
+}
+
+type FaultSimulationResponse struct {
+	Kind     string      `json:"kind"`
+	Location string      `json:"location"`
+	Pairs    []FaultPair `json:"pairs"`
+}
+
+type DFAProgress struct {
+	Pairs      int `json:"pairs"`
+	Candidates int `json:"candidates"`
+}
+
+type DFAResponse struct {
+	Kind                   string        `json:"kind"`
+	Location               string        `json:"location"`
+	Pairs                  int           `json:"pairs"`
+	LastRoundKeyCandidates int           `json:"last_round_key_candidates"`
+	LastRoundKeys          []string      `json:"last_round_keys"`
+	MasterKeys             []string      `json:"master_keys"`
+	CorrectKey             string        `json:"correct_key"`
+	Success                bool          `json:"success"`
+	Progress               []DFAProgress `json:"progress"`
+}
+
+type AlgebraStats struct {
+	Decisions    int `json:"decisions"`
+	Propagations int `json:"propagations"`
+	Conflicts    int `json:"conflicts"`
+	Learnt       int `json:"learnt"`
+}
+
+type AlgebraSolveResponse struct {
+	Variables int             `json:"variables"`
+	Clauses   int             `json:"clauses"`
+	Count     int             `json:"count"`
+	Keys      []BruteForceKey `json:"keys"`
+	Complete  bool            `json:"complete"`
+	ElapsedMs int64           `json:"elapsed_ms"`
+	Stats     AlgebraStats    `json:"stats"`
+}
+
+type KDFParams struct {
+	Iterations  int    `json:"iterations,omitempty"`
+	N           int    `json:"n,omitempty"`
+	R           int    `json:"r,omitempty"`
+	Memory      uint32 `json:"memory,omitempty"`
+	Parallelism int    `json:"parallelism,omitempty"`
+}
+
+// KDFCrackReport 对比攻击密钥空间与按口令逐个猜测的代价。
+type KDFCrackReport struct {
+	Attack          string       `json:"attack"`
+	Pairs           []AttackPair `json:"pairs"`
+	Candidates      []string     `json:"candidates"`
+	Recovered       bool         `json:"recovered"`
+	Keyspace        uint64       `json:"keyspace"`
+	ElapsedMs       float64      `json:"elapsed_ms"`
+	PasswordGuessMs float64      `json:"password_guess_ms"`
+	// GuessesInSameTime 为同样时间内按口令猜测能尝试的次数。
+	GuessesInSameTime float64 `json:"guesses_in_same_time"`
+}
+
+type KDFDeriveResponse struct {
+	Key       string          `json:"key"`
+	KeyBits   int             `json:"key_bits"`
+	Salt      string          `json:"salt"`
+	Algorithm string          `json:"algorithm"`
+	Params    KDFParams       `json:"params"`
+	DeriveMs  float64         `json:"derive_ms"`
+	Crack     *KDFCrackReport `json:"crack,omitempty"`
+}
+
+type RewrapResult struct {
+	Ciphertext  string `json:"ciphertext"`
+	FromVersion int    `json:"from_version"`
+	ToVersion   int    `json:"to_version"`
+}
+
+type RewrapResponse struct {
+	KeyID          string         `json:"key_id"`
+	CurrentVersion int            `json:"current_version"`
+	Results        []RewrapResult `json:"results"`
+}
+
+type DiskSector struct {
+	Sector uint64 `json:"sector"`
+	Data   string `json:"data"`
+}
+
+// PRPPage 为置换表的一页，NextOffset 在到达表尾时省略。
+type PRPPage struct {
+	Size       int   `json:"size"`
+	Offset     int   `json:"offset"`
+	Values     []int `json:"values"`
+	NextOffset *int  `json:"next_offset,omitempty"`
+}
+
+// BatchResult 为单个操作的结果，OK 为 false 时只有 Error 有意义。
+type BatchResult struct {
+	Index      int             `json:"index"`
+	ID         json.RawMessage `json:"id,omitempty"`
+	OK         bool            `json:"ok"`
+	Output     string          `json:"output,omitempty"`
+	IV         string          `json:"iv,omitempty"`
+	KeyVersion int             `json:"key_version,omitempty"`
+	Trace      *BlockTrace     `json:"trace,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// BlockTrace 为加密一个分组的轮密钥与逐步状态。
+type BlockTrace struct {
+	RoundKeys []string    `json:"round_keys"`
+	Steps     []TraceStep `json:"steps"`
+}
+
+type TraceStep struct {
+	Round int    `json:"round"`
+	Step  string `json:"step"`
+	State string `json:"state"`
+}
+
+type BatchResponse struct {
+	Total     int           `json:"total"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	ElapsedMs float64       `json:"elapsed_ms"`
+	Results   []BatchResult `json:"results"`
+}
@@ -16,4 +16,42 @@ func InitRouter(r *gin.Engine) {
 	r.POST("/encrypt/cbc", handler.EncryptCBC)
 	r.POST("/decrypt/cbc", handler.DecryptCBC)
 	r.POST("/attack/meet-in-the-middle", handler.MeetInTheMiddleAttack)
+	r.POST("/attack/brute-force", handler.BruteForceAttack)
+	r.POST("/analysis/sbox", handler.AnalyzeSBox)
+	r.POST("/analysis/timing", handler.TimingAnalysis)
+	r.POST("/sca/traces", handler.GeneratePowerTraces)
+	r.POST("/sca/traces/download", handler.DownloadPowerTraces)
+	r.POST("/sca/cpa", handler.CorrelationPowerAnalysis)
+	r.POST("/fault/simulate", handler.SimulateFaults)
+	r.POST("/fault/dfa", handler.DifferentialFaultAnalysis)
+	r.POST("/algebra/export", handler.ExportAlgebra)
+	r.POST("/algebra/solve", handler.SolveAlgebra)
+	r.POST("/files/encrypt", handler.EncryptFile)
+	r.POST("/files/decrypt", handler.DecryptFile)
+	r.POST("/kdf/derive", handler.DeriveKDF)
+	r.POST("/keys", handler.CreateKey)
+	r.GET("/keys", handler.ListKeys)
+	r.POST("/keys/rewrap", handler.RewrapCiphertexts)
+	r.GET("/keys/:name", handler.GetKey)
+	r.DELETE("/keys/:name", handler.DeleteKey)
+	r.POST("/keys/:name/rotate", handler.RotateKey)
+	r.POST("/keywrap/wrap", handler.WrapKey)
+	r.POST("/keywrap/unwrap", handler.UnwrapKey)
+	r.POST("/disk/encrypt", handler.EncryptDisk)
+	r.POST("/disk/decrypt", handler.DecryptDisk)
+	r.POST("/fpe/encrypt", handler.EncryptFPE)
+	r.POST("/fpe/decrypt", handler.DecryptFPE)
+	r.POST("/prp/permute", handler.PermutePRP)
+	r.POST("/prp/shuffle", handler.ShufflePRP)
+	r.GET("/prp/permutation", handler.PermutationTable)
+	r.GET("/random/bytes", handler.RandomBytes)
+	r.POST("/random/test", handler.RandomTest)
+	r.POST("/hash", handler.HashMessage)
+	r.POST("/hash/collision", handler.FindHashCollision)
+	r.POST("/hash/second-preimage", handler.FindHashSecondPreimage)
+	r.POST("/analysis/ciphertext", handler.AnalyzeCiphertext)
+	r.GET("/analysis/penguin", handler.PenguinDemo)
+	r.POST("/image/encrypt", handler.EncryptImage)
+	r.POST("/batch", handler.Batch)
+	r.GET("/codebook", handler.ExportCodebook)
 }
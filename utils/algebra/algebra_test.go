@@ -0,0 +1,115 @@
+package algebra
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"S-AES/utils/saes"
+)
+
+func TestRecoverKeys(t *testing.T) {
+	c := saes.StandardCipher()
+	const key = 0xA73B
+	var pairs []Pair
+	for _, p := range []uint16{0x6F6B, 0x1234} {
+		pairs = append(pairs, Pair{Plaintext: p, Ciphertext: c.EncryptBlock(p, key)})
+	}
+
+	res, err := RecoverKeys(c, pairs, 0, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Complete {
+		t.Fatal("未完成全部解的枚举")
+	}
+	found := false
+	for _, k := range res.Keys {
+		if k == key {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("未恢复出密钥 0x%04X，得到 %04X", key, res.Keys)
+	}
+
+	// 冲突预算由全部求解调用共用：等于总冲突数时成功，只够一半时失败。
+	total := res.Stats.Conflicts
+	if _, err := RecoverKeys(c, pairs, 0, Options{MaxConflicts: total}); err != nil {
+		t.Fatalf("预算 %d 应足够: %v", total, err)
+	}
+	if _, err := RecoverKeys(c, pairs, 0, Options{MaxConflicts: total / 2}); err == nil {
+		t.Fatalf("预算 %d 不足时应报错", total/2)
+	}
+}
+
+func TestRecoverKeysCustomParams(t *testing.T) {
+	p := saes.DefaultParams()
+	p.FinalMixColumns = true
+	c, err := saes.NewCipher(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const key = 0x4567
+	pairs := []Pair{{0x0000, c.EncryptBlock(0x0000, key)}, {0xFFFF, c.EncryptBlock(0xFFFF, key)}}
+
+	res, err := RecoverKeys(c, pairs, 1, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Keys) != 1 {
+		t.Fatalf("期望找到 1 个密钥，得到 %d 个", len(res.Keys))
+	}
+}
+
+func TestCNFEncodesCipher(t *testing.T) {
+	// 固定密钥变量后，正确密钥应可满足，错误密钥应不可满足。
+	c := saes.StandardCipher()
+	pair := Pair{0x6F6B, 0x0738}
+	sys, err := Build(c, []Pair{pair})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		key uint16
+		sat bool
+	}{{0xA73B, true}, {0xA73A, false}} {
+		f := sys.CNF()
+		for i, v := range sys.KeyVars {
+			f.Clauses = append(f.Clauses, []int{-literal(v, tc.key>>i&1 == 1)})
+		}
+		res, err := Solve(f, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Satisfiable != tc.sat {
+			t.Errorf("密钥 0x%04X：可满足性 %v，期望 %v", tc.key, res.Satisfiable, tc.sat)
+		}
+	}
+}
+
+func TestWriters(t *testing.T) {
+	sys, err := Build(saes.StandardCipher(), []Pair{{0x6F6B, 0x0738}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var anf, cnf, xcnf bytes.Buffer
+	if err := WriteANF(&anf, sys); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteDIMACS(&cnf, sys); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteXCNF(&xcnf, sys); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(anf.String(), "*") {
+		t.Error("ANF 输出缺少非线性项")
+	}
+	if !strings.Contains(cnf.String(), "\np cnf ") {
+		t.Error("DIMACS 输出缺少 p cnf 行")
+	}
+	if !strings.Contains(xcnf.String(), "\nx ") {
+		t.Error("XCNF 输出缺少 XOR 子句")
+	}
+}
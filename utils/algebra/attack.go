@@ -0,0 +1,67 @@
+package algebra
+
+import (
+	"fmt"
+
+	"S-AES/utils/saes"
+)
+
+// RecoverResult 汇总代数攻击的结果与求解开销。
+type RecoverResult struct {
+	Keys    []uint16
+	Vars    int
+	Clauses int
+	// Complete 为 true 表示已证明不存在其他密钥（未因 limit 截断）。
+	Complete bool
+	Stats    Stats
+}
+
+// RecoverKeys 建立方程组并反复求解，每找到一个密钥便加入阻塞子句，直到无解或达到 limit 个。
+// opts.MaxConflicts 为全部求解调用共用的冲突预算，而不是每次调用各自的上限。
+func RecoverKeys(c *saes.Cipher, pairs []Pair, limit int, opts Options) (*RecoverResult, error) {
+	sys, err := Build(c, pairs)
+	if err != nil {
+		return nil, err
+	}
+	f := sys.CNF()
+	opts.Priority = append(opts.Priority, sys.KeyVars[:]...)
+	result := &RecoverResult{Vars: f.NumVars, Clauses: len(f.Clauses)}
+	budget := opts.MaxConflicts
+	for limit <= 0 || len(result.Keys) < limit {
+		if budget > 0 {
+			opts.MaxConflicts = budget - result.Stats.Conflicts
+			if opts.MaxConflicts <= 0 {
+				return nil, fmt.Errorf("超过冲突上限 %d", budget)
+			}
+		}
+		res, err := Solve(f, opts)
+		if err != nil {
+			if budget > 0 {
+				return nil, fmt.Errorf("超过冲突上限 %d", budget)
+			}
+			return nil, err
+		}
+		result.Stats.Decisions += res.Stats.Decisions
+		result.Stats.Propagations += res.Stats.Propagations
+		result.Stats.Conflicts += res.Stats.Conflicts
+		result.Stats.Learnt += res.Stats.Learnt
+		if !res.Satisfiable {
+			result.Complete = true
+			break
+		}
+		key := sys.KeyFromModel(res.Model)
+		for _, p := range pairs {
+			if c.EncryptBlock(p.Plaintext, key) != p.Ciphertext {
+				return nil, fmt.Errorf("求解得到的密钥 0x%04X 与明密文对不符，模型可能有误", key)
+			}
+		}
+		result.Keys = append(result.Keys, key)
+
+		block := make([]int, 0, len(sys.KeyVars))
+		for i, v := range sys.KeyVars {
+			block = append(block, literal(v, key>>i&1 == 1))
+		}
+		f.Clauses = append(f.Clauses, block)
+	}
+	return result, nil
+}
@@ -0,0 +1,94 @@
+package algebra
+
+// xorChunk 为直接展开为 CNF 的异或约束的最大变量数，更长的约束以辅助变量切分。
+const xorChunk = 4
+
+// CNF 为 DIMACS 风格的合取范式：正整数 v 表示变量 v，-v 表示其否定。
+type CNF struct {
+	NumVars int
+	Clauses [][]int
+	// XORs 仅在 CryptoMiniSat 格式下使用：每项要求 Vars 之和等于 RHS。
+	XORs []XORClause
+}
+
+// XORClause 为一条异或约束。
+type XORClause struct {
+	Vars []int
+	RHS  bool
+}
+
+// CNF 把方程组转换为纯 CNF：S 盒以真值表子句表示，线性方程切分后展开。
+func (s *System) CNF() *CNF {
+	f := &CNF{NumVars: s.NumVars}
+	s.sboxClauses(f)
+	for _, l := range s.Linear {
+		f.addXOR(l.Vars, l.Const)
+	}
+	return f
+}
+
+// XCNF 与 CNF 相同，但线性方程保留为 CryptoMiniSat 的 XOR 子句。
+func (s *System) XCNF() *CNF {
+	f := &CNF{NumVars: s.NumVars}
+	s.sboxClauses(f)
+	for _, l := range s.Linear {
+		f.XORs = append(f.XORs, XORClause{Vars: append([]int(nil), l.Vars...), RHS: l.Const})
+	}
+	return f
+}
+
+// sboxClauses 对每个 S 盒输入取值 v 与输出位 o 生成子句 (In != v) ∨ (Out[o] = S(v)[o])。
+func (s *System) sboxClauses(f *CNF) {
+	for _, sc := range s.SBoxes {
+		for v := 0; v < 16; v++ {
+			y := s.SBox[v]
+			for o := 0; o < 4; o++ {
+				clause := make([]int, 0, 5)
+				for i := 0; i < 4; i++ {
+					clause = append(clause, literal(sc.In[i], v>>i&1 == 1))
+				}
+				clause = append(clause, literal(sc.Out[o], y>>o&1 == 0))
+				f.Clauses = append(f.Clauses, clause)
+			}
+		}
+	}
+}
+
+// addXOR 把 vars 之和等于 rhs 展开为子句；变量较多时引入 t = v1+v2+v3 逐段缩短。
+func (f *CNF) addXOR(vars []int, rhs bool) {
+	for len(vars) > xorChunk {
+		f.NumVars++
+		t := f.NumVars
+		f.xorClauses([]int{vars[0], vars[1], vars[2], t}, false)
+		vars = append([]int{t}, vars[3:]...)
+	}
+	f.xorClauses(vars, rhs)
+}
+
+// xorClauses 为每个奇偶性不符的赋值生成一条排除它的子句。
+func (f *CNF) xorClauses(vars []int, rhs bool) {
+	if len(vars) == 0 {
+		if rhs {
+			f.Clauses = append(f.Clauses, []int{})
+		}
+		return
+	}
+	for a := 0; a < 1<<len(vars); a++ {
+		if (popcount(a)&1 == 1) == rhs {
+			continue
+		}
+		clause := make([]int, len(vars))
+		for i, v := range vars {
+			clause[i] = literal(v, a>>i&1 == 1)
+		}
+		f.Clauses = append(f.Clauses, clause)
+	}
+}
+
+// literal 返回在变量取值为 assigned 时为假的文字。
+func literal(v int, assigned bool) int {
+	if assigned {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,91 @@
+package algebra
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteANF 以多项式形式逐行写出方程组，每行表示一个等于 0 的多项式（Bosphorus 兼容格式）。
+func WriteANF(w io.Writer, s *System) error {
+	bw := bufio.NewWriter(w)
+	writeHeader(bw, s, "c")
+	for _, l := range s.Linear {
+		terms := make([]string, 0, len(l.Vars)+1)
+		for _, v := range l.Vars {
+			terms = append(terms, fmt.Sprintf("x%d", v))
+		}
+		if l.Const {
+			terms = append(terms, "1")
+		}
+		fmt.Fprintln(bw, strings.Join(terms, " + "))
+	}
+	for _, sc := range s.SBoxes {
+		for o := 0; o < 4; o++ {
+			terms := []string{fmt.Sprintf("x%d", sc.Out[o])}
+			for _, m := range monomials(s.anf[o]) {
+				if m == 0 {
+					terms = append(terms, "1")
+					continue
+				}
+				factors := make([]string, 0, 4)
+				for i := 0; i < 4; i++ {
+					if m>>i&1 == 1 {
+						factors = append(factors, fmt.Sprintf("x%d", sc.In[i]))
+					}
+				}
+				terms = append(terms, strings.Join(factors, "*"))
+			}
+			fmt.Fprintln(bw, strings.Join(terms, " + "))
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteDIMACS 写出纯 CNF。
+func WriteDIMACS(w io.Writer, s *System) error {
+	return writeCNF(w, s, s.CNF())
+}
+
+// WriteXCNF 写出带 XOR 子句的 CryptoMiniSat 格式（"x" 开头的行）。
+func WriteXCNF(w io.Writer, s *System) error {
+	return writeCNF(w, s, s.XCNF())
+}
+
+func writeCNF(w io.Writer, s *System, f *CNF) error {
+	bw := bufio.NewWriter(w)
+	writeHeader(bw, s, "c")
+	fmt.Fprintf(bw, "p cnf %d %d\n", f.NumVars, len(f.Clauses)+len(f.XORs))
+	for _, clause := range f.Clauses {
+		for _, lit := range clause {
+			fmt.Fprintf(bw, "%d ", lit)
+		}
+		fmt.Fprintln(bw, "0")
+	}
+	for _, x := range f.XORs {
+		if len(x.Vars) == 0 {
+			if x.RHS {
+				fmt.Fprintln(bw, "0")
+			}
+			continue
+		}
+		// "x a b c 0" 要求 a+b+c = 1；右端为 0 时对第一个变量取反。
+		bw.WriteString("x")
+		for i, v := range x.Vars {
+			if i == 0 && !x.RHS {
+				v = -v
+			}
+			fmt.Fprintf(bw, " %d", v)
+		}
+		fmt.Fprintln(bw, " 0")
+	}
+	return bw.Flush()
+}
+
+// writeHeader 以注释记录变量规模与密钥变量编号，便于外部求解器的结果回读。
+func writeHeader(w io.Writer, s *System, comment string) {
+	fmt.Fprintf(w, "%s S-AES 代数模型：%d 个变量，%d 个线性方程，%d 个 S 盒\n",
+		comment, s.NumVars, len(s.Linear), len(s.SBoxes))
+	fmt.Fprintf(w, "%s 密钥位 k15..k0 对应变量 x%d..x%d\n", comment, s.KeyVars[15], s.KeyVars[0])
+}
@@ -0,0 +1,302 @@
+package algebra
+
+import "fmt"
+
+// 求解器为带两文字监视、1UIP 冲突分析与非时序回跳的最小 CDCL 实现。
+// 文字内部编码为 2v（正）与 2v+1（负）。
+
+// Stats 记录一次求解的搜索规模。
+type Stats struct {
+	Decisions    int `json:"decisions"`
+	Propagations int `json:"propagations"`
+	Conflicts    int `json:"conflicts"`
+	Learnt       int `json:"learnt"`
+}
+
+// Options 控制求解过程。
+type Options struct {
+	// MaxConflicts 为冲突数上限，0 表示不限。
+	MaxConflicts int
+	// Priority 中的变量初始活跃度更高，优先作为决策变量（如密钥变量）。
+	Priority []int
+}
+
+// Result 为求解结果，Model[v] 为变量 v 的取值（下标 0 不使用）。
+type Result struct {
+	Satisfiable bool
+	Model       []bool
+	Stats       Stats
+}
+
+type solver struct {
+	nVars    int
+	clauses  [][]int
+	watches  [][]int
+	assign   []int8
+	phase    []bool
+	level    []int
+	reason   []int
+	trail    []int
+	trailLim []int
+	qhead    int
+	activity []float64
+	inc      float64
+	seen     []bool
+	stats    Stats
+}
+
+// Solve 求解 f 的纯 CNF 部分（XORs 须已展开）；超出冲突上限时返回错误。
+func Solve(f *CNF, opts Options) (*Result, error) {
+	if len(f.XORs) > 0 {
+		return nil, fmt.Errorf("求解器不直接支持 XOR 子句，请使用 CNF()")
+	}
+	s := &solver{
+		nVars:    f.NumVars,
+		watches:  make([][]int, 2*(f.NumVars+1)),
+		assign:   make([]int8, f.NumVars+1),
+		phase:    make([]bool, f.NumVars+1),
+		level:    make([]int, f.NumVars+1),
+		reason:   make([]int, f.NumVars+1),
+		activity: make([]float64, f.NumVars+1),
+		seen:     make([]bool, f.NumVars+1),
+		inc:      1,
+	}
+	for v := range s.assign {
+		s.assign[v] = -1
+	}
+	for _, v := range opts.Priority {
+		s.activity[v] = 1
+	}
+	for _, clause := range f.Clauses {
+		if !s.addClause(clause) {
+			return &Result{Stats: s.stats}, nil
+		}
+	}
+	for {
+		confl := s.propagate()
+		if confl >= 0 {
+			s.stats.Conflicts++
+			if len(s.trailLim) == 0 {
+				return &Result{Stats: s.stats}, nil
+			}
+			if opts.MaxConflicts > 0 && s.stats.Conflicts > opts.MaxConflicts {
+				return nil, fmt.Errorf("超过冲突上限 %d", opts.MaxConflicts)
+			}
+			learnt, back := s.analyze(confl)
+			s.backtrack(back)
+			s.learn(learnt)
+			s.inc /= 0.95
+			continue
+		}
+		v := s.pickBranch()
+		if v == 0 {
+			model := make([]bool, s.nVars+1)
+			for i := 1; i <= s.nVars; i++ {
+				model[i] = s.assign[i] == 1
+			}
+			return &Result{Satisfiable: true, Model: model, Stats: s.stats}, nil
+		}
+		s.stats.Decisions++
+		s.trailLim = append(s.trailLim, len(s.trail))
+		lit := 2 * v
+		if !s.phase[v] {
+			lit++
+		}
+		s.enqueue(lit, -1)
+	}
+}
+
+func encode(lit int) int {
+	if lit < 0 {
+		return 2*(-lit) + 1
+	}
+	return 2 * lit
+}
+
+// value 返回文字的取值：1 为真，0 为假，-1 为未赋值。
+func (s *solver) value(lit int) int8 {
+	a := s.assign[lit>>1]
+	if a < 0 {
+		return -1
+	}
+	return a ^ int8(lit&1)
+}
+
+// addClause 在第 0 层加入一条原始子句，返回 false 表示公式已不可满足。
+func (s *solver) addClause(clause []int) bool {
+	lits := make([]int, 0, len(clause))
+	for _, l := range clause {
+		lit := encode(l)
+		switch s.value(lit) {
+		case 1:
+			return true
+		case 0:
+			continue
+		}
+		lits = append(lits, lit)
+	}
+	switch len(lits) {
+	case 0:
+		return false
+	case 1:
+		s.enqueue(lits[0], -1)
+		return s.propagate() < 0
+	}
+	idx := len(s.clauses)
+	s.clauses = append(s.clauses, lits)
+	s.watches[lits[0]] = append(s.watches[lits[0]], idx)
+	s.watches[lits[1]] = append(s.watches[lits[1]], idx)
+	return true
+}
+
+func (s *solver) enqueue(lit, reason int) {
+	v := lit >> 1
+	s.assign[v] = int8(lit&1) ^ 1
+	s.level[v] = len(s.trailLim)
+	s.reason[v] = reason
+	s.trail = append(s.trail, lit)
+}
+
+// propagate 执行单元传播，返回冲突子句下标，无冲突时返回 -1。
+func (s *solver) propagate() int {
+	for s.qhead < len(s.trail) {
+		falseLit := s.trail[s.qhead] ^ 1
+		s.qhead++
+		s.stats.Propagations++
+		ws := s.watches[falseLit]
+		kept := ws[:0]
+		for i := 0; i < len(ws); i++ {
+			ci := ws[i]
+			c := s.clauses[ci]
+			if c[0] == falseLit {
+				c[0], c[1] = c[1], c[0]
+			}
+			if s.value(c[0]) == 1 {
+				kept = append(kept, ci)
+				continue
+			}
+			moved := false
+			for k := 2; k < len(c); k++ {
+				if s.value(c[k]) != 0 {
+					c[1], c[k] = c[k], c[1]
+					s.watches[c[1]] = append(s.watches[c[1]], ci)
+					moved = true
+					break
+				}
+			}
+			if moved {
+				continue
+			}
+			kept = append(kept, ci)
+			if s.value(c[0]) == 0 {
+				kept = append(kept, ws[i+1:]...)
+				s.watches[falseLit] = kept
+				s.qhead = len(s.trail)
+				return ci
+			}
+			s.enqueue(c[0], ci)
+		}
+		s.watches[falseLit] = kept
+	}
+	return -1
+}
+
+// analyze 由冲突子句推导 1UIP 学习子句，返回子句与回跳层数。
+func (s *solver) analyze(confl int) ([]int, int) {
+	learnt := []int{0}
+	pathC := 0
+	p := -1
+	idx := len(s.trail) - 1
+	current := len(s.trailLim)
+	for {
+		c := s.clauses[confl]
+		start := 0
+		if p >= 0 {
+			start = 1
+		}
+		for _, q := range c[start:] {
+			v := q >> 1
+			if s.seen[v] || s.level[v] == 0 {
+				continue
+			}
+			s.seen[v] = true
+			s.bump(v)
+			if s.level[v] >= current {
+				pathC++
+			} else {
+				learnt = append(learnt, q)
+			}
+		}
+		for !s.seen[s.trail[idx]>>1] {
+			idx--
+		}
+		p = s.trail[idx]
+		idx--
+		confl = s.reason[p>>1]
+		s.seen[p>>1] = false
+		pathC--
+		if pathC == 0 {
+			break
+		}
+	}
+	learnt[0] = p ^ 1
+
+	back := 0
+	for i := 1; i < len(learnt); i++ {
+		s.seen[learnt[i]>>1] = false
+		if lv := s.level[learnt[i]>>1]; lv > back {
+			back = lv
+			learnt[1], learnt[i] = learnt[i], learnt[1]
+		}
+	}
+	return learnt, back
+}
+
+func (s *solver) learn(learnt []int) {
+	s.stats.Learnt++
+	if len(learnt) == 1 {
+		s.enqueue(learnt[0], -1)
+		return
+	}
+	idx := len(s.clauses)
+	s.clauses = append(s.clauses, learnt)
+	s.watches[learnt[0]] = append(s.watches[learnt[0]], idx)
+	s.watches[learnt[1]] = append(s.watches[learnt[1]], idx)
+	s.enqueue(learnt[0], idx)
+}
+
+func (s *solver) backtrack(level int) {
+	if len(s.trailLim) <= level {
+		return
+	}
+	start := s.trailLim[level]
+	for _, lit := range s.trail[start:] {
+		v := lit >> 1
+		s.phase[v] = s.assign[v] == 1
+		s.assign[v] = -1
+	}
+	s.trail = s.trail[:start]
+	s.trailLim = s.trailLim[:level]
+	s.qhead = start
+}
+
+func (s *solver) bump(v int) {
+	s.activity[v] += s.inc
+	if s.activity[v] > 1e100 {
+		for i := range s.activity {
+			s.activity[i] *= 1e-100
+		}
+		s.inc *= 1e-100
+	}
+}
+
+// pickBranch 选择活跃度最高的未赋值变量，全部赋值时返回 0。
+func (s *solver) pickBranch() int {
+	best := 0
+	for v := 1; v <= s.nVars; v++ {
+		if s.assign[v] < 0 && (best == 0 || s.activity[v] > s.activity[best]) {
+			best = v
+		}
+	}
+	return best
+}
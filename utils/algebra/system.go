@@ -0,0 +1,231 @@
+// Package algebra 把已知明密文对下的 S-AES 表示为 GF(2) 上的方程组，
+// 导出为 ANF、DIMACS CNF 与 CryptoMiniSat XOR 子句格式，并用内置的 CDCL 求解器恢复密钥。
+package algebra
+
+import (
+	"fmt"
+	"sort"
+
+	"S-AES/utils/saes"
+)
+
+// Lin 为 GF(2) 上的仿射表达式：Vars 中变量之和再加常数 Const。
+type Lin struct {
+	Vars  []int
+	Const bool
+}
+
+func constLin(b bool) Lin {
+	return Lin{Const: b}
+}
+
+func varLin(v int) Lin {
+	return Lin{Vars: []int{v}}
+}
+
+// xor 返回 a + b（变量集合取对称差）。
+func (a Lin) xor(b Lin) Lin {
+	out := Lin{Const: a.Const != b.Const, Vars: make([]int, 0, len(a.Vars)+len(b.Vars))}
+	i, j := 0, 0
+	for i < len(a.Vars) || j < len(b.Vars) {
+		switch {
+		case j == len(b.Vars) || (i < len(a.Vars) && a.Vars[i] < b.Vars[j]):
+			out.Vars = append(out.Vars, a.Vars[i])
+			i++
+		case i == len(a.Vars) || b.Vars[j] < a.Vars[i]:
+			out.Vars = append(out.Vars, b.Vars[j])
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	return out
+}
+
+// SBoxConstraint 约束 Out = S(In)，下标 0 为最低位。
+type SBoxConstraint struct {
+	In  [4]int
+	Out [4]int
+}
+
+// Pair 为一组已知的明文与密文。
+type Pair struct {
+	Plaintext  uint16
+	Ciphertext uint16
+}
+
+// System 为 S-AES 的代数模型：线性方程 Linear[i] = 0 与 S 盒约束。
+type System struct {
+	NumVars int
+	// KeyVars[i] 为主密钥第 i 位（0 为最低位）对应的变量编号，变量从 1 开始编号。
+	KeyVars [16]int
+	Linear  []Lin
+	SBoxes  []SBoxConstraint
+	// SBox 为模型使用的 S 盒，anf[o] 为其第 o 个输出位的 ANF 系数。
+	SBox [16]byte
+	anf  [4][16]byte
+}
+
+// Build 依照 c 的 S 盒、列混淆、轮数与密钥扩展为所有明密文对建立方程组，各对共享密钥变量。
+func Build(c *saes.Cipher, pairs []Pair) (*System, error) {
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("至少需要一个明文/密文对")
+	}
+	s := &System{SBox: c.SBox()}
+	for o := 0; o < 4; o++ {
+		s.anf[o] = saes.CoordinateANF(s.SBox, o)
+	}
+
+	var key [16]Lin
+	for i := 15; i >= 0; i-- {
+		s.KeyVars[i] = s.newVar()
+		key[i] = varLin(s.KeyVars[i])
+	}
+	roundKeys := s.expandKey(c, key)
+
+	mix := linearMatrix(c.MixColumnsState)
+	shift := linearMatrix(saes.ShiftRowsState)
+	for _, p := range pairs {
+		var state [16]Lin
+		for i := range state {
+			state[i] = constLin(p.Plaintext>>i&1 == 1)
+		}
+		state = xorState(state, roundKeys[0])
+		for r := 1; r <= c.Rounds(); r++ {
+			for n := 0; n < 4; n++ {
+				base := 12 - 4*n
+				out := s.sbox([4]Lin{state[base], state[base+1], state[base+2], state[base+3]})
+				copy(state[base:base+4], out[:])
+			}
+			state = applyMatrix(shift, state)
+			if r < c.Rounds() || c.Params().FinalMixColumns {
+				state = applyMatrix(mix, state)
+			}
+			state = xorState(state, roundKeys[r])
+		}
+		for i := range state {
+			s.addLinear(state[i].xor(constLin(p.Ciphertext>>i&1 == 1)))
+		}
+	}
+	return s, nil
+}
+
+// KeyFromModel 从变量赋值中读出主密钥。
+func (s *System) KeyFromModel(model []bool) uint16 {
+	var key uint16
+	for i, v := range s.KeyVars {
+		if model[v] {
+			key |= 1 << i
+		}
+	}
+	return key
+}
+
+func (s *System) newVar() int {
+	s.NumVars++
+	return s.NumVars
+}
+
+func (s *System) addLinear(l Lin) {
+	if len(l.Vars) == 0 && !l.Const {
+		return
+	}
+	s.Linear = append(s.Linear, l)
+}
+
+// sbox 为一次 S 盒代换引入输入/输出变量；输入恰为单个变量时直接复用。
+func (s *System) sbox(in [4]Lin) [4]Lin {
+	var sc SBoxConstraint
+	var out [4]Lin
+	for i, l := range in {
+		if len(l.Vars) == 1 && !l.Const {
+			sc.In[i] = l.Vars[0]
+			continue
+		}
+		sc.In[i] = s.newVar()
+		s.addLinear(l.xor(varLin(sc.In[i])))
+	}
+	for o := range out {
+		sc.Out[o] = s.newVar()
+		out[o] = varLin(sc.Out[o])
+	}
+	s.SBoxes = append(s.SBoxes, sc)
+	return out
+}
+
+// expandKey 以符号方式执行密钥扩展，返回各轮轮密钥的 16 个比特表达式。
+func (s *System) expandKey(c *saes.Cipher, key [16]Lin) [][16]Lin {
+	rcon := c.RoundConstants()
+	roundKeys := make([][16]Lin, c.Rounds()+1)
+	roundKeys[0] = key
+	rk := key
+	for i := 1; i <= c.Rounds(); i++ {
+		// g：RotNib 后低半字节来自奇数字的高半字节，高半字节来自其低半字节。
+		lo := s.sbox([4]Lin{rk[4], rk[5], rk[6], rk[7]})
+		hi := s.sbox([4]Lin{rk[0], rk[1], rk[2], rk[3]})
+		var g [8]Lin
+		copy(g[0:4], lo[:])
+		copy(g[4:8], hi[:])
+		for bit := 0; bit < 8; bit++ {
+			g[bit] = g[bit].xor(constLin(rcon[i-1]>>bit&1 == 1))
+			rk[8+bit] = rk[8+bit].xor(g[bit])
+			rk[bit] = rk[bit].xor(rk[8+bit])
+		}
+		roundKeys[i] = rk
+	}
+	return roundKeys
+}
+
+// linearMatrix 通过单位向量探测 16 位线性变换，row[o] 为输出位 o 依赖的输入位掩码。
+func linearMatrix(f func(uint16) uint16) [16]uint16 {
+	var rows [16]uint16
+	for i := 0; i < 16; i++ {
+		col := f(1 << i)
+		for o := 0; o < 16; o++ {
+			if col>>o&1 == 1 {
+				rows[o] |= 1 << i
+			}
+		}
+	}
+	return rows
+}
+
+func applyMatrix(rows [16]uint16, state [16]Lin) [16]Lin {
+	var out [16]Lin
+	for o, mask := range rows {
+		for i := 0; i < 16; i++ {
+			if mask>>i&1 == 1 {
+				out[o] = out[o].xor(state[i])
+			}
+		}
+	}
+	return out
+}
+
+func xorState(a, b [16]Lin) [16]Lin {
+	for i := range a {
+		a[i] = a[i].xor(b[i])
+	}
+	return a
+}
+
+// monomials 返回 ANF 中系数为 1 的单项式，按次数降序排列。
+func monomials(anf [16]byte) []int {
+	var ms []int
+	for m, coef := range anf {
+		if coef == 1 {
+			ms = append(ms, m)
+		}
+	}
+	sort.SliceStable(ms, func(i, j int) bool { return popcount(ms[i]) > popcount(ms[j]) })
+	return ms
+}
+
+func popcount(m int) int {
+	n := 0
+	for ; m != 0; m &= m - 1 {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,238 @@
+// Package armor 定义 S-AES 密文的 ASCII 封装格式（仿 PEM/OpenPGP），携带模式、IV、填充与密钥长度，
+// 以 CRC-24 校验粘贴过程中的损坏。
+//
+//	-----BEGIN S-AES MESSAGE-----
+//	Version: 1
+//	Mode: cbc
+//	Padding: pkcs7
+//	IV: 0x1A2B
+//	Key-Bits: 16
+//
+//	FkubVEGGk79ZS6jW
+//	=ehw0
+//	-----END S-AES MESSAGE-----
+package armor
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"S-AES/utils/saes"
+)
+
+const (
+	BeginLine = "-----BEGIN S-AES MESSAGE-----"
+	EndLine   = "-----END S-AES MESSAGE-----"
+	// Version 为当前写出的封装版本。
+	Version = 1
+	// lineWidth 为 Base64 正文的换行宽度。
+	lineWidth = 64
+)
+
+// Message 为封装的内容；IV 为 nil 表示该模式不使用初始向量。
+type Message struct {
+	Mode    saes.Mode
+	Padding saes.Padding
+	IV      *uint16
+	KeyBits int
+	// KeyID 为可选的密钥标识。
+	KeyID string
+	Data  []byte
+}
+
+// Encode 输出封装文本，末尾带换行。
+func Encode(m *Message) string {
+	var sb strings.Builder
+	sb.WriteString(BeginLine + "\n")
+	fmt.Fprintf(&sb, "Version: %d\n", Version)
+	fmt.Fprintf(&sb, "Mode: %s\n", m.Mode)
+	fmt.Fprintf(&sb, "Padding: %s\n", m.Padding)
+	if m.IV != nil {
+		fmt.Fprintf(&sb, "IV: 0x%04X\n", *m.IV)
+	}
+	fmt.Fprintf(&sb, "Key-Bits: %d\n", m.KeyBits)
+	if m.KeyID != "" {
+		fmt.Fprintf(&sb, "Key-ID: %s\n", m.KeyID)
+	}
+	sb.WriteString("\n")
+
+	body := base64.StdEncoding.EncodeToString(m.Data)
+	for len(body) > lineWidth {
+		sb.WriteString(body[:lineWidth] + "\n")
+		body = body[lineWidth:]
+	}
+	if body != "" {
+		sb.WriteString(body + "\n")
+	}
+	crc := CRC24(m.Data)
+	sb.WriteString("=" + base64.StdEncoding.EncodeToString([]byte{byte(crc >> 16), byte(crc >> 8), byte(crc)}) + "\n")
+	sb.WriteString(EndLine + "\n")
+	return sb.String()
+}
+
+// IsArmored 报告 s 是否包含封装的起始行。
+func IsArmored(s string) bool {
+	return strings.Contains(s, BeginLine)
+}
+
+// Decode 解析封装文本，忽略起始行之前与结束行之后的内容，并校验 CRC-24。
+func Decode(s string) (*Message, error) {
+	start := strings.Index(s, BeginLine)
+	if start < 0 {
+		return nil, fmt.Errorf("未找到 %s", BeginLine)
+	}
+	sc := bufio.NewScanner(strings.NewReader(s[start+len(BeginLine):]))
+
+	m := &Message{}
+	seen := map[string]bool{}
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			if len(seen) > 0 {
+				break
+			}
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("无效的头部行: %q", line)
+		}
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if seen[strings.ToLower(name)] {
+			return nil, fmt.Errorf("重复的头部字段: %s", name)
+		}
+		seen[strings.ToLower(name)] = true
+		if err := m.setHeader(name, value); err != nil {
+			return nil, err
+		}
+	}
+	for _, required := range []string{"version", "mode", "padding", "key-bits"} {
+		if !seen[required] {
+			return nil, fmt.Errorf("缺少头部字段: %s", required)
+		}
+	}
+	if m.Mode.NeedsIV() && m.IV == nil {
+		return nil, fmt.Errorf("%s 模式需要 IV 头部字段", m.Mode)
+	}
+
+	var body strings.Builder
+	var checksum string
+	ended := false
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == EndLine {
+			ended = true
+			break
+		}
+		if strings.HasPrefix(line, "=") && len(line) == 5 {
+			checksum = line[1:]
+			continue
+		}
+		body.WriteString(line)
+	}
+	if !ended {
+		return nil, fmt.Errorf("未找到 %s", EndLine)
+	}
+	data, err := base64.StdEncoding.DecodeString(body.String())
+	if err != nil {
+		return nil, fmt.Errorf("正文 Base64 解码失败: %w", err)
+	}
+	if checksum == "" {
+		return nil, fmt.Errorf("缺少 CRC-24 校验行")
+	}
+	sum, err := base64.StdEncoding.DecodeString(checksum)
+	if err != nil || len(sum) != 3 {
+		return nil, fmt.Errorf("无效的 CRC-24 校验行")
+	}
+	if want := uint32(sum[0])<<16 | uint32(sum[1])<<8 | uint32(sum[2]); CRC24(data) != want {
+		return nil, fmt.Errorf("CRC-24 校验失败，密文在复制过程中可能已损坏")
+	}
+	m.Data = data
+	return m, nil
+}
+
+func (m *Message) setHeader(name, value string) error {
+	var err error
+	switch strings.ToLower(name) {
+	case "version":
+		if value != strconv.Itoa(Version) {
+			return fmt.Errorf("不支持的封装版本: %s", value)
+		}
+	case "mode":
+		m.Mode, err = saes.ParseMode(value)
+	case "padding":
+		m.Padding, err = saes.ParsePadding(value)
+	case "iv":
+		var iv uint64
+		iv, err = strconv.ParseUint(strings.TrimPrefix(strings.ToLower(value), "0x"), 16, 16)
+		if err != nil {
+			return fmt.Errorf("无效的 IV: %s", value)
+		}
+		v := uint16(iv)
+		m.IV = &v
+	case "key-bits":
+		m.KeyBits, err = strconv.Atoi(value)
+		if err == nil && m.KeyBits != 16 && m.KeyBits != 32 && m.KeyBits != 48 {
+			err = fmt.Errorf("无效的密钥位数: %s", value)
+		}
+	case "key-id":
+		m.KeyID = value
+	default:
+		// 未知字段按 PEM 惯例忽略，便于后续版本扩展。
+	}
+	return err
+}
+
+// CRC24 按 OpenPGP（RFC 4880 第 6.1 节）计算 CRC-24。
+func CRC24(data []byte) uint32 {
+	crc := uint32(0xB704CE)
+	for _, b := range data {
+		crc ^= uint32(b) << 16
+		for i := 0; i < 8; i++ {
+			crc <<= 1
+			if crc&0x1000000 != 0 {
+				crc ^= 0x1864CFB
+			}
+		}
+	}
+	return crc & 0xFFFFFF
+}
+
+// Seal 用 kb 以指定参数加密 plaintext 并封装。
+func Seal(kb *saes.KeyedBlock, mode saes.Mode, padding saes.Padding, iv uint16, plaintext []byte) (string, error) {
+	data, err := saes.EncryptBytes(kb, mode, padding, iv, plaintext)
+	if err != nil {
+		return "", err
+	}
+	m := &Message{Mode: mode, Padding: padding, KeyBits: kb.KeyBits(), Data: data}
+	if mode.NeedsIV() {
+		m.IV = &iv
+	}
+	if mode.Streaming() {
+		m.Padding = saes.PaddingNone
+	}
+	return Encode(m), nil
+}
+
+// Open 解析封装文本并按其中记录的参数解密。
+func Open(kb *saes.KeyedBlock, text string) ([]byte, *Message, error) {
+	m, err := Decode(text)
+	if err != nil {
+		return nil, nil, err
+	}
+	if m.KeyBits != kb.KeyBits() {
+		return nil, nil, fmt.Errorf("密文使用 %d 位密钥，提供的密钥为 %d 位", m.KeyBits, kb.KeyBits())
+	}
+	var iv uint16
+	if m.IV != nil {
+		iv = *m.IV
+	}
+	plain, err := saes.DecryptBytes(kb, m.Mode, m.Padding, iv, m.Data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return plain, m, nil
+}
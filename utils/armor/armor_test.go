@@ -0,0 +1,61 @@
+package armor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"S-AES/utils/saes"
+)
+
+func TestCRC24(t *testing.T) {
+	// RFC 4880 CRC-24 的标准校验值。
+	if got := CRC24([]byte("123456789")); got != 0x21CF02 {
+		t.Fatalf("CRC24 = %06X，期望 21CF02", got)
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	kb, err := saes.NewKeyedBlock(saes.Standard, "0x123456789ABC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := bytes.Repeat([]byte("copy-paste safe "), 10)
+	for _, mode := range saes.Modes {
+		text, err := Seal(kb, mode, saes.PaddingPKCS7, 0xBEEF, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 前后附带聊天内容、行尾为 CRLF 时仍可解析。
+		pasted := "见下：\r\n" + strings.ReplaceAll(text, "\n", "\r\n") + "谢谢\n"
+		got, m, err := Open(kb, pasted)
+		if err != nil {
+			t.Fatalf("%s: %v", mode, err)
+		}
+		if !bytes.Equal(got, msg) || m.Mode != mode || m.KeyBits != 48 {
+			t.Fatalf("%s: 解封结果不一致", mode)
+		}
+	}
+}
+
+func TestDecodeRejectsCorruption(t *testing.T) {
+	kb, _ := saes.NewKeyedBlock(saes.Standard, "0x4AF5")
+	text, err := Seal(kb, saes.ModeCBC, saes.PaddingPKCS7, 0x1A2B, []byte("I love CQU!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrupted := strings.Replace(text, "FkubVEGGk79ZS6jW", "FkubVEGGk79ZS6jX", 1)
+	if _, err := Decode(corrupted); err == nil || !strings.Contains(err.Error(), "CRC-24") {
+		t.Errorf("正文损坏应导致 CRC-24 校验失败，得到 %v", err)
+	}
+	if _, err := Decode(strings.Replace(text, "IV: 0x1A2B\n", "", 1)); err == nil {
+		t.Error("CBC 缺少 IV 时应当报错")
+	}
+	if _, err := Decode(strings.Replace(text, EndLine, "", 1)); err == nil {
+		t.Error("缺少结束行时应当报错")
+	}
+	other, _ := saes.NewKeyedBlock(saes.Standard, "0x12345678")
+	if _, _, err := Open(other, text); err == nil {
+		t.Error("密钥长度不符时应当报错")
+	}
+}
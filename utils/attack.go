@@ -57,39 +57,45 @@ func ParseBlockString(input string) (uint16, error) {
 	return uint16(value), nil
 }
 
-// MeetInTheMiddleAttack 执行中间相遇攻击，返回所有匹配的 (K1, K2) 组合。
-func MeetInTheMiddleAttack(pairs []PlainCipherPair) ([]KeyPair, error) {
+// MeetInTheMiddleAttack 对 bc 的双重加密执行中间相遇攻击，返回所有匹配的 (K1, K2) 组合。
+func MeetInTheMiddleAttack(bc saes.BlockCipher, pairs []PlainCipherPair) ([]KeyPair, error) {
 	if len(pairs) == 0 {
 		return nil, fmt.Errorf("至少需要一个明文/密文对")
 	}
 
-	forwardMap := make(map[uint16][]uint16, 1<<16)
+	bc = saes.Accelerate(bc)
 	first := pairs[0]
 
-	for k1 := 0; k1 <= 0xFFFF; k1++ {
-		mid := saes.EncryptBlockRaw(first.Plain, uint16(k1))
-		forwardMap[mid] = append(forwardMap[mid], uint16(k1))
+	// 按中间值对 K1 做计数排序：order[start[m]:start[m+1]] 为所有满足 E(P, K1) = m 的 K1。
+	keys := allKeys()
+	mids := make([]uint16, 1<<16)
+	saes.EncryptBatchWith(bc, []uint16{first.Plain}, keys, mids)
+	start := make([]int32, 1<<16+1)
+	for _, mid := range mids {
+		start[int(mid)+1]++
+	}
+	for i := 1; i < len(start); i++ {
+		start[i] += start[i-1]
+	}
+	order := make([]uint16, 1<<16)
+	next := append([]int32(nil), start[:1<<16]...)
+	for k1, mid := range mids {
+		order[next[mid]] = uint16(k1)
+		next[mid]++
 	}
 
-	candidates := make(map[KeyPair]struct{})
-	for k2 := 0; k2 <= 0xFFFF; k2++ {
-		mid := saes.DecryptBlockRaw(first.Cipher, uint16(k2))
-		possible, ok := forwardMap[mid]
-		if !ok {
-			continue
-		}
-		for _, k1 := range possible {
+	backward := make([]uint16, 1<<16)
+	saes.DecryptBatchWith(bc, []uint16{first.Cipher}, keys, backward)
+	results := make([]KeyPair, 0)
+	for k2, mid := range backward {
+		for _, k1 := range order[start[mid]:start[int(mid)+1]] {
 			pair := KeyPair{K1: k1, K2: uint16(k2)}
-			if verifyCandidate(pair, pairs) {
-				candidates[pair] = struct{}{}
+			if verifyCandidate(bc, pair, pairs) {
+				results = append(results, pair)
 			}
 		}
 	}
 
-	results := make([]KeyPair, 0, len(candidates))
-	for pair := range candidates {
-		results = append(results, pair)
-	}
 	sort.Slice(results, func(i, j int) bool {
 		if results[i].K1 == results[j].K1 {
 			return results[i].K2 < results[j].K2
@@ -100,9 +106,47 @@ func MeetInTheMiddleAttack(pairs []PlainCipherPair) ([]KeyPair, error) {
 	return results, nil
 }
 
-func verifyCandidate(pair KeyPair, pairs []PlainCipherPair) bool {
+// BruteForceAttack 穷举单重加密的全部 2^16 个密钥，返回与所有明密文对一致的密钥。
+func BruteForceAttack(bc saes.BlockCipher, pairs []PlainCipherPair) ([]uint16, error) {
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("至少需要一个明文/密文对")
+	}
+
+	bc = saes.Accelerate(bc)
+	keys := allKeys()
+	out := make([]uint16, 1<<16)
+	saes.EncryptBatchWith(bc, []uint16{pairs[0].Plain}, keys, out)
+
+	results := make([]uint16, 0)
+	for k, c := range out {
+		if c != pairs[0].Cipher {
+			continue
+		}
+		ok := true
+		for _, pc := range pairs[1:] {
+			if bc.EncryptBlock(pc.Plain, uint16(k)) != pc.Cipher {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			results = append(results, uint16(k))
+		}
+	}
+	return results, nil
+}
+
+func allKeys() []uint16 {
+	keys := make([]uint16, 1<<16)
+	for k := range keys {
+		keys[k] = uint16(k)
+	}
+	return keys
+}
+
+func verifyCandidate(bc saes.BlockCipher, pair KeyPair, pairs []PlainCipherPair) bool {
 	for _, pc := range pairs {
-		if saes.DoubleEncryptRaw(pc.Plain, pair.K1, pair.K2) != pc.Cipher {
+		if bc.EncryptBlock(bc.EncryptBlock(pc.Plain, pair.K1), pair.K2) != pc.Cipher {
 			return false
 		}
 	}
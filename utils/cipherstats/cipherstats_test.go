@@ -0,0 +1,64 @@
+package cipherstats
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math"
+	"testing"
+
+	"S-AES/utils/saes"
+)
+
+func TestGuessMode(t *testing.T) {
+	kb, err := saes.NewKeyedBlock(saes.Standard, "0x2D55")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := bytes.Repeat([]byte("attack at dawn!!"), 64)
+	ecb, _ := saes.EncryptBytes(kb, saes.ModeECB, saes.PaddingNone, 0, plain)
+	cbc, _ := saes.EncryptBytes(kb, saes.ModeCBC, saes.PaddingNone, 0x1234, plain)
+
+	r, err := Analyze(ecb, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Mode != ModeECB || r.UniqueBlocks > 8 || len(r.TopBlocks) != 3 {
+		t.Fatalf("ECB 密文应被识别: %+v", r)
+	}
+	if r.TopBlocks[0].Count != 64 {
+		t.Fatalf("高频分组计数不符: %+v", r.TopBlocks)
+	}
+	if r, _ := Analyze(cbc, 3); r.Mode != ModeChained {
+		t.Fatalf("CBC 密文不应被判为 ECB: %+v", r)
+	}
+
+	random := make([]byte, 1<<16)
+	rand.Read(random)
+	r, _ = Analyze(random, 0)
+	if r.Mode != ModeChained {
+		t.Fatalf("随机数据不应被判为 ECB: 重复 %d，期望 %.1f", r.RepeatedBlocks, r.ExpectedRepeats)
+	}
+	if r.ByteEntropy < 7.9 || math.Abs(r.IndexOfCoincidence-1) > 0.05 {
+		t.Fatalf("随机数据的熵 %.3f 或重合指数 %.3f 异常", r.ByteEntropy, r.IndexOfCoincidence)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	r, err := Analyze([]byte{0, 1, 0, 1, 0, 1, 2, 3, 4}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Blocks != 4 || r.UniqueBlocks != 2 || r.RepeatedBlocks != 2 {
+		t.Fatalf("分组统计不符: %+v", r)
+	}
+	want := []FrequencyBin{{1, 1}, {3, 1}}
+	if len(r.Histogram) != 2 || r.Histogram[0] != want[0] || r.Histogram[1] != want[1] {
+		t.Fatalf("直方图为 %+v，期望 %+v", r.Histogram, want)
+	}
+	if r.Mode != ModeUnknown {
+		t.Fatal("分组过少时应无法判断")
+	}
+	if _, err := Analyze([]byte{1}, 0); err == nil {
+		t.Fatal("不足一个分组应报错")
+	}
+}
@@ -0,0 +1,92 @@
+package codebook
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"S-AES/utils/saes"
+)
+
+func TestStats(t *testing.T) {
+	// 轮换数、最长轮换与摘要为本实现生成的回归值；不动点逐个用 EncryptBlockRaw 核对。
+	st := New(saes.RawKey(0xA73B)).Stats()
+	want := []string{"0x08E9", "0x0BC9", "0x68E2", "0x6BC2"}
+	for _, p := range []uint16{0x08E9, 0x0BC9, 0x68E2, 0x6BC2} {
+		if c := saes.EncryptBlockRaw(p, 0xA73B); c != p {
+			t.Fatalf("0x%04X 加密为 0x%04X，不是不动点", p, c)
+		}
+	}
+	if strings.Join(st.FixedPoints, ",") != strings.Join(want, ",") {
+		t.Fatalf("不动点为 %v，期望 %v", st.FixedPoints, want)
+	}
+	if st.Cycles != 50 || st.LongestCycle != 41820 || st.Parity != "even" {
+		t.Fatalf("轮换结构不符: %+v", st)
+	}
+	if st.SHA256 != "028033b13b11c91806066da8b563068edae00eaf455184b5e91079f4eeaa5937" {
+		t.Fatalf("对照表摘要为 %s", st.SHA256)
+	}
+	total := 0
+	for _, cl := range st.CycleLengths {
+		total += cl.Length * cl.Count
+	}
+	if total != Size || st.CycleLengths[0].Length != st.LongestCycle {
+		t.Fatalf("轮换长度之和为 %d", total)
+	}
+
+	if st := New(saes.RawKey(0x2D55)).Stats(); len(st.FixedPoints) != 0 || st.FixedPoints == nil {
+		t.Fatalf("0x2D55 应没有不动点且输出空列表: %v", st.FixedPoints)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	kb, _ := saes.NewKeyedBlock(saes.Standard, "0x2D55")
+	cb := New(kb)
+	if raw := New(saes.RawKey(0x2D55)); !bytes.Equal(cb.Bytes(), raw.Bytes()) {
+		t.Fatal("KeyedBlock 与 EncryptBlockRaw 的对照表不一致")
+	}
+	if cb.Encrypt(0x1234) != 0x1560 {
+		t.Fatalf("E(0x1234) = %04x", cb.Encrypt(0x1234))
+	}
+
+	var buf bytes.Buffer
+	if err := cb.Write(&buf, FormatCSV); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != Size+1 || lines[0] != "plaintext,ciphertext" || lines[0x1234+1] != "1234,1560" {
+		t.Fatalf("CSV 共 %d 行，第 0x1234 项为 %q", len(lines), lines[0x1234+1])
+	}
+
+	buf.Reset()
+	if err := cb.Write(&buf, FormatJSONL); err != nil {
+		t.Fatal(err)
+	}
+	sc := bufio.NewScanner(&buf)
+	n := 0
+	for sc.Scan() {
+		if n == 0x1234 && sc.Text() != `{"plaintext":"1234","ciphertext":"1560"}` {
+			t.Fatalf("JSON lines 第 0x1234 行为 %s", sc.Text())
+		}
+		n++
+	}
+	if n != Size {
+		t.Fatalf("JSON lines 共 %d 行", n)
+	}
+
+	buf.Reset()
+	if err := cb.Write(&buf, FormatBinary); err != nil {
+		t.Fatal(err)
+	}
+	if b := buf.Bytes(); len(b) != 2*Size || b[2*0x1234] != 0x15 || b[2*0x1234+1] != 0x60 {
+		t.Fatal("二进制对照表不符")
+	}
+
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Fatal("未知格式应报错")
+	}
+	if f, _ := ParseFormat(" BIN "); f != FormatBinary {
+		t.Fatal("格式名称应不区分大小写")
+	}
+}
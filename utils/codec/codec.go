@@ -0,0 +1,174 @@
+// Package codec 统一各接口的输入/输出编码：十六进制、Base64、Base64URL、Base32、
+// 按字节或按半字节分组的位串、ASCII 文本与原始字节。
+package codec
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Encoding 为编码名称。
+type Encoding string
+
+const (
+	Hex       Encoding = "hex"
+	Base64    Encoding = "base64"
+	Base64URL Encoding = "base64url"
+	Base32    Encoding = "base32"
+	// Bits 为按字节分组、以空格分隔的位串，如 "01101111 01101011"。
+	Bits Encoding = "bits"
+	// Nibbles 为按半字节分组的位串，如 "0110 1111 0110 1011"。
+	Nibbles Encoding = "nibbles"
+	// Text 为 ASCII 文本。
+	Text Encoding = "text"
+	// Raw 为原始字节，仅用于 application/octet-stream 请求与响应。
+	Raw Encoding = "raw"
+)
+
+// Encodings 列出全部支持的编码。
+var Encodings = []Encoding{Hex, Base64, Base64URL, Base32, Bits, Nibbles, Text, Raw}
+
+// Parse 解析编码名称（不区分大小写）；空字符串返回 def。
+func Parse(name string, def Encoding) (Encoding, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return def, nil
+	}
+	switch name {
+	case "binary", "bin":
+		return Bits, nil
+	case "ascii":
+		return Text, nil
+	}
+	for _, e := range Encodings {
+		if name == string(e) {
+			return e, nil
+		}
+	}
+	names := make([]string, len(Encodings))
+	for i, e := range Encodings {
+		names[i] = string(e)
+	}
+	return "", fmt.Errorf("不支持的编码: %s（可选 %s）", name, strings.Join(names, "、"))
+}
+
+// Decode 把 s 按编码 e 还原为字节。除 Text 与 Raw 外均忽略空白，十六进制可带 0x 前缀。
+func Decode(e Encoding, s string) ([]byte, error) {
+	switch e {
+	case Text:
+		for _, r := range s {
+			if r > 0x7F {
+				return nil, fmt.Errorf("检测到非 ASCII 字符: %q", r)
+			}
+		}
+		return []byte(s), nil
+	case Raw:
+		return []byte(s), nil
+	}
+
+	compact := strings.Join(strings.Fields(s), "")
+	var (
+		out []byte
+		err error
+	)
+	switch e {
+	case Hex:
+		if strings.HasPrefix(strings.ToLower(compact), "0x") {
+			compact = compact[2:]
+		}
+		out, err = hex.DecodeString(compact)
+	case Base64:
+		out, err = base64.StdEncoding.DecodeString(compact)
+	case Base64URL:
+		// 同时接受带填充与不带填充的形式。
+		out, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(compact, "="))
+	case Base32:
+		out, err = base32.StdEncoding.DecodeString(strings.ToUpper(compact))
+	case Bits, Nibbles:
+		out, err = decodeBits(compact)
+	default:
+		return nil, fmt.Errorf("不支持的编码: %s", e)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s 解码失败: %w", e, err)
+	}
+	return out, nil
+}
+
+// Encode 按编码 e 输出 data。
+func Encode(e Encoding, data []byte) (string, error) {
+	switch e {
+	case Hex:
+		return hex.EncodeToString(data), nil
+	case Base64:
+		return base64.StdEncoding.EncodeToString(data), nil
+	case Base64URL:
+		return base64.RawURLEncoding.EncodeToString(data), nil
+	case Base32:
+		return base32.StdEncoding.EncodeToString(data), nil
+	case Bits:
+		return groupBits(data, 8), nil
+	case Nibbles:
+		return groupBits(data, 4), nil
+	case Text:
+		for _, b := range data {
+			if b > 0x7F {
+				return "", fmt.Errorf("结果包含非 ASCII 字符 0x%02X，请改用其他输出编码", b)
+			}
+		}
+		return string(data), nil
+	case Raw:
+		return string(data), nil
+	default:
+		return "", fmt.Errorf("不支持的编码: %s", e)
+	}
+}
+
+// DecodeBlocks 按原有的分组输入格式解析：0x 开头为十六进制，否则为位串；长度须为 16 位的整数倍。
+func DecodeBlocks(s string) ([]byte, error) {
+	compact := strings.Join(strings.Fields(s), "")
+	e := Bits
+	if strings.HasPrefix(strings.ToLower(compact), "0x") {
+		e = Hex
+	}
+	out, err := Decode(e, compact)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 || len(out)%2 != 0 {
+		return nil, fmt.Errorf("输入必须是 16 位分组的整数倍")
+	}
+	return out, nil
+}
+
+func decodeBits(s string) ([]byte, error) {
+	if len(s)%8 != 0 {
+		return nil, fmt.Errorf("位串长度必须是 8 的倍数")
+	}
+	out := make([]byte, len(s)/8)
+	for i, ch := range s {
+		if ch != '0' && ch != '1' {
+			return nil, fmt.Errorf("仅支持字符0或1")
+		}
+		if ch == '1' {
+			out[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return out, nil
+}
+
+func groupBits(data []byte, width int) string {
+	var sb strings.Builder
+	for i, b := range data {
+		for j := 0; j < 8; j += width {
+			if i > 0 || j > 0 {
+				sb.WriteByte(' ')
+			}
+			fmt.Fprintf(&sb, "%0*b", width, (b<<j)>>(8-width))
+		}
+	}
+	return sb.String()
+}
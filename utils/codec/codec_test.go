@@ -0,0 +1,50 @@
+package codec
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	data := []byte{0x6F, 0x6B, 0x00, 0xFF, 0xA5}
+	for _, e := range Encodings {
+		s, err := Encode(e, data)
+		if e == Text {
+			if err == nil {
+				t.Error("text 编码应拒绝非 ASCII 字节")
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", e, err)
+		}
+		got, err := Decode(e, s)
+		if err != nil || !bytes.Equal(got, data) {
+			t.Errorf("%s: %q 解码为 %x, %v", e, s, got, err)
+		}
+	}
+}
+
+func TestFormats(t *testing.T) {
+	data := []byte{0x6F, 0x6B}
+	for e, want := range map[Encoding]string{
+		Hex:       "6f6b",
+		Bits:      "01101111 01101011",
+		Nibbles:   "0110 1111 0110 1011",
+		Base64URL: "b2s",
+		Base32:    "N5VQ====",
+	} {
+		if got, _ := Encode(e, data); got != want {
+			t.Errorf("%s: 得到 %q，期望 %q", e, got, want)
+		}
+	}
+	for _, in := range []string{"0x6F6B", "0110111101101011", "0110 1111\n0110 1011", "0x 6f 6b"} {
+		got, err := DecodeBlocks(in)
+		if err != nil || !bytes.Equal(got, data) {
+			t.Errorf("DecodeBlocks(%q) = %x, %v", in, got, err)
+		}
+	}
+	if _, err := DecodeBlocks("0x6F"); err == nil {
+		t.Error("不足 16 位时应当报错")
+	}
+}
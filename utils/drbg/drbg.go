@@ -0,0 +1,302 @@
+// Package drbg 实现以 S-AES 分组核心为底层的 CTR-DRBG（仿 NIST SP 800-90A，无派生函数）。
+// 内部状态为密钥 Key（16/32/48 位）与 16 位计数器 V，seedlen = keylen + 16 位。
+//
+// 两轮 S-AES 在同一密钥下对连续计数器的输出在统计上明显可区分（randtest 的自相关检验
+// 可以直接检出），因此与标准 CTR-DRBG 不同，这里每输出一个分组就执行一次 Update 更换密钥。
+// 默认使用 48 位密钥：状态只有 32 位时很快进入循环。它只适合教学与统计对比，不能替代 crypto/rand。
+package drbg
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"S-AES/utils/saes"
+)
+
+const (
+	// MaxRequestBytes 为单次 Generate 的最大输出。
+	MaxRequestBytes = 1 << 15
+	// DefaultKeyBits 为默认密钥长度。
+	DefaultKeyBits = 48
+	// DefaultReseedInterval 为两次自动重播种之间允许的 Generate 调用次数。
+	DefaultReseedInterval = 1 << 10
+	// MaxAdditionalInput 为附加输入与个性化串的最大字节数。
+	MaxAdditionalInput = 1 << 10
+)
+
+// ErrReseedRequired 表示固定种子的实例已到达重播种间隔，且没有熵源可用。
+var ErrReseedRequired = errors.New("已到达重播种间隔，固定种子的实例需要显式重播种")
+
+// Config 为实例化参数，零值表示 48 位密钥、标准 S-AES、crypto/rand 熵源。
+type Config struct {
+	// KeyBits 为 16、32 或 48，后两者按二重/三重级联加密；0 表示 DefaultKeyBits。
+	KeyBits int
+	// ReseedInterval 为自动重播种前允许的 Generate 调用次数，0 表示 DefaultReseedInterval。
+	ReseedInterval uint64
+	// Cipher 为底层分组实现，nil 表示 saes.Standard。
+	Cipher saes.BlockCipher
+	// Entropy 为熵源，nil 表示 crypto/rand。
+	Entropy io.Reader
+	// Seed 非空时代替熵源作为实例化的熵输入（至少 SeedLen 字节），输出完全可复现；
+	// 此时不会自动重播种，到达间隔后 Generate 返回 ErrReseedRequired。
+	Seed []byte
+	// Personalization 为个性化串，与熵输入异或。
+	Personalization []byte
+}
+
+// DRBG 为并发安全的确定性随机比特生成器，实现 io.Reader。
+type DRBG struct {
+	mu       sync.Mutex
+	bc       saes.BlockCipher
+	entropy  io.Reader
+	seeded   bool
+	interval uint64
+	keyLen   int
+	key      []byte
+	v        uint16
+	kb       *saes.KeyedBlock
+	counter  uint64
+	reseeds  uint64
+	total    uint64
+}
+
+// Stats 为生成器的计数器快照。
+type Stats struct {
+	KeyBits        int    `json:"key_bits"`
+	ReseedCounter  uint64 `json:"reseed_counter"`
+	ReseedInterval uint64 `json:"reseed_interval"`
+	Reseeds        uint64 `json:"reseeds"`
+	BytesGenerated uint64 `json:"bytes_generated"`
+}
+
+// New 实例化生成器：读取 SeedLen 字节熵输入，与个性化串异或后对全零状态执行 Update。
+func New(cfg Config) (*DRBG, error) {
+	bits := cfg.KeyBits
+	if bits == 0 {
+		bits = DefaultKeyBits
+	}
+	if bits != 16 && bits != 32 && bits != 48 {
+		return nil, fmt.Errorf("密钥长度必须为 16、32 或 48 位")
+	}
+	if len(cfg.Personalization) > MaxAdditionalInput {
+		return nil, fmt.Errorf("个性化串最多 %d 字节", MaxAdditionalInput)
+	}
+	d := &DRBG{
+		bc:       cfg.Cipher,
+		entropy:  cfg.Entropy,
+		interval: cfg.ReseedInterval,
+		keyLen:   bits / 8,
+	}
+	if d.bc == nil {
+		d.bc = saes.Standard
+	}
+	if d.entropy == nil {
+		d.entropy = rand.Reader
+	}
+	if d.interval == 0 {
+		d.interval = DefaultReseedInterval
+	}
+	d.key = make([]byte, d.keyLen)
+	if err := d.rekey(); err != nil {
+		return nil, err
+	}
+
+	var seed []byte
+	if len(cfg.Seed) > 0 {
+		if len(cfg.Seed) < d.SeedLen() {
+			return nil, fmt.Errorf("种子至少需要 %d 字节", d.SeedLen())
+		}
+		seed = append([]byte(nil), cfg.Seed...)
+		d.seeded = true
+	} else {
+		var err error
+		if seed, err = d.readEntropy(); err != nil {
+			return nil, err
+		}
+	}
+	if err := d.update(xorInput(fold(seed, d.SeedLen()), cfg.Personalization)); err != nil {
+		return nil, err
+	}
+	d.counter = 1
+	return d, nil
+}
+
+// SeedLen 返回种子长度（字节），即密钥长度加一个分组。
+func (d *DRBG) SeedLen() int {
+	return d.keyLen + 2
+}
+
+// Reseed 从熵源读取新的熵输入，与附加输入一起更新状态并清零重播种计数器。
+func (d *DRBG) Reseed(additional []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.reseed(additional)
+}
+
+// ReseedWith 以调用方提供的熵输入重播种，用于固定种子的可复现实例。
+func (d *DRBG) ReseedWith(entropy, additional []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(entropy) < d.SeedLen() {
+		return fmt.Errorf("熵输入至少需要 %d 字节", d.SeedLen())
+	}
+	return d.reseedFrom(entropy, additional)
+}
+
+// Generate 向 out 写入 len(out) 字节，len(out) 不超过 MaxRequestBytes。
+// 计数器超过重播种间隔时先自动重播种（固定种子的实例返回 ErrReseedRequired）。
+func (d *DRBG) Generate(out, additional []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.generate(out, additional)
+}
+
+// Read 实现 io.Reader，按 MaxRequestBytes 拆分为多次 Generate。
+func (d *DRBG) Read(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for off := 0; off < len(p); off += MaxRequestBytes {
+		end := min(off+MaxRequestBytes, len(p))
+		if err := d.generate(p[off:end], nil); err != nil {
+			return off, err
+		}
+	}
+	return len(p), nil
+}
+
+// Stats 返回计数器快照。
+func (d *DRBG) Stats() Stats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return Stats{
+		KeyBits:        d.keyLen * 8,
+		ReseedCounter:  d.counter,
+		ReseedInterval: d.interval,
+		Reseeds:        d.reseeds,
+		BytesGenerated: d.total,
+	}
+}
+
+func (d *DRBG) generate(out, additional []byte) error {
+	if len(out) > MaxRequestBytes {
+		return fmt.Errorf("单次最多生成 %d 字节", MaxRequestBytes)
+	}
+	if len(additional) > MaxAdditionalInput {
+		return fmt.Errorf("附加输入最多 %d 字节", MaxAdditionalInput)
+	}
+	if d.counter > d.interval {
+		if d.seeded {
+			return ErrReseedRequired
+		}
+		if err := d.reseed(additional); err != nil {
+			return err
+		}
+		additional = nil
+	}
+	var provided []byte
+	if len(additional) > 0 {
+		provided = fold(additional, d.SeedLen())
+		if err := d.update(provided); err != nil {
+			return err
+		}
+	}
+	for off := 0; off < len(out); off += 2 {
+		d.v++
+		block := d.kb.EncryptBlock(d.v)
+		out[off] = byte(block >> 8)
+		if off+1 < len(out) {
+			out[off+1] = byte(block)
+		}
+		// 最后一次 Update 按规范带上附加输入，其余只用于更换密钥。
+		if off+2 < len(out) {
+			if err := d.update(nil); err != nil {
+				return err
+			}
+		}
+	}
+	if err := d.update(provided); err != nil {
+		return err
+	}
+	d.counter++
+	d.total += uint64(len(out))
+	return nil
+}
+
+func (d *DRBG) reseed(additional []byte) error {
+	if len(additional) > MaxAdditionalInput {
+		return fmt.Errorf("附加输入最多 %d 字节", MaxAdditionalInput)
+	}
+	entropy, err := d.readEntropy()
+	if err != nil {
+		return err
+	}
+	return d.reseedFrom(entropy, additional)
+}
+
+func (d *DRBG) reseedFrom(entropy, additional []byte) error {
+	if err := d.update(xorInput(fold(entropy, d.SeedLen()), additional)); err != nil {
+		return err
+	}
+	d.counter = 1
+	d.reseeds++
+	return nil
+}
+
+func (d *DRBG) readEntropy() ([]byte, error) {
+	buf := make([]byte, d.SeedLen())
+	if _, err := io.ReadFull(d.entropy, buf); err != nil {
+		return nil, fmt.Errorf("读取熵源失败: %w", err)
+	}
+	return buf, nil
+}
+
+// update 为 CTR_DRBG_Update：以当前密钥加密 V+1, V+2, … 得到 seedlen 字节，
+// 与 provided（可为 nil）异或后前 keylen 字节作新密钥、后 2 字节作新 V。
+func (d *DRBG) update(provided []byte) error {
+	temp := make([]byte, 0, d.SeedLen()+1)
+	for len(temp) < d.SeedLen() {
+		d.v++
+		block := d.kb.EncryptBlock(d.v)
+		temp = append(temp, byte(block>>8), byte(block))
+	}
+	temp = temp[:d.SeedLen()]
+	for i := range provided {
+		temp[i] ^= provided[i]
+	}
+	copy(d.key, temp[:d.keyLen])
+	d.v = uint16(temp[d.keyLen])<<8 | uint16(temp[d.keyLen+1])
+	return d.rekey()
+}
+
+func (d *DRBG) rekey() error {
+	kb, err := saes.NewKeyedBlock(d.bc, "0x"+hex.EncodeToString(d.key))
+	if err != nil {
+		return err
+	}
+	d.kb = kb
+	return nil
+}
+
+// fold 把任意长度的输入按异或折叠为 n 字节（无派生函数时对较长输入的简化处理）。
+func fold(in []byte, n int) []byte {
+	out := make([]byte, n)
+	for i, b := range in {
+		out[i%n] ^= b
+	}
+	return out
+}
+
+func xorInput(seed, extra []byte) []byte {
+	if len(extra) == 0 {
+		return seed
+	}
+	f := fold(extra, len(seed))
+	for i := range seed {
+		seed[i] ^= f[i]
+	}
+	return seed
+}
@@ -0,0 +1,160 @@
+// Package dudect 实现 dudect 风格的计时泄露检测：对“固定输入”与“随机输入”两类样本计时，
+// 用 Welch t 检验判断两类执行时间分布是否可区分。
+package dudect
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"time"
+)
+
+// Threshold 为判定存在计时泄露的 |t| 阈值（dudect 的惯例取 4.5）。
+const Threshold = 4.5
+
+// Config 控制一次检测的规模。
+type Config struct {
+	// Measurements 为总采样次数，两类样本随机交错。
+	Measurements int
+	// Repeat 为每次采样内重复调用的次数，用于摊薄计时器本身的开销。
+	Repeat int
+	// FixedBlock 为“固定输入”类使用的明文。
+	FixedBlock uint16
+	// Key 为两类样本共用的秘密密钥。
+	Key uint16
+	// Seed 决定分类顺序与随机明文，便于复现。
+	Seed uint64
+}
+
+// CropResult 为按某个百分位裁剪长尾后的 t 检验结果。
+type CropResult struct {
+	Percentile float64
+	T          float64
+}
+
+// Result 汇总一次检测的统计量。
+type Result struct {
+	Measurements int
+	FixedCount   int
+	RandomCount  int
+	FixedMean    float64
+	RandomMean   float64
+	// T 为未裁剪样本的 Welch t 统计量。
+	T     float64
+	Crops []CropResult
+	// MaxAbsT 为所有裁剪结果中 |t| 的最大值。
+	MaxAbsT float64
+	Leaky   bool
+}
+
+// DefaultConfig 返回适合交互式演示的参数。
+func DefaultConfig() Config {
+	return Config{
+		Measurements: 200000,
+		Repeat:       8,
+		FixedBlock:   0x0000,
+		Key:          0xA73B,
+		Seed:         1,
+	}
+}
+
+// Run 对 encrypt 执行固定输入与随机输入两类计时并做 Welch t 检验。
+func Run(encrypt func(block, key uint16) uint16, cfg Config) (Result, error) {
+	if cfg.Measurements < 100 {
+		return Result{}, fmt.Errorf("采样次数至少为 100")
+	}
+	if cfg.Repeat < 1 {
+		cfg.Repeat = 1
+	}
+
+	rng := rand.New(rand.NewPCG(cfg.Seed, cfg.Seed^0x9E3779B97F4A7C15))
+	classes := make([]bool, cfg.Measurements)
+	inputs := make([]uint16, cfg.Measurements)
+	for i := range classes {
+		classes[i] = rng.IntN(2) == 1
+		if classes[i] {
+			inputs[i] = uint16(rng.Uint32())
+		} else {
+			inputs[i] = cfg.FixedBlock
+		}
+	}
+
+	// 预热，避免首批样本受缓存与 CPU 频率影响。
+	var sink uint16
+	for i := 0; i < 1000; i++ {
+		sink ^= encrypt(inputs[i%len(inputs)], cfg.Key)
+	}
+
+	samples := make([]float64, cfg.Measurements)
+	for i, in := range inputs {
+		start := time.Now()
+		for r := 0; r < cfg.Repeat; r++ {
+			sink ^= encrypt(in, cfg.Key)
+		}
+		samples[i] = float64(time.Since(start).Nanoseconds()) / float64(cfg.Repeat)
+	}
+	_ = sink
+	return analyze(samples, classes), nil
+}
+
+// analyze 对全部样本及按各百分位裁剪长尾后的样本做 Welch t 检验，classes[i] 为真表示随机输入类。
+func analyze(samples []float64, classes []bool) Result {
+	res := Result{Measurements: len(samples)}
+	all := welch(samples, classes, math.Inf(1))
+	res.FixedCount, res.RandomCount = all.n[0], all.n[1]
+	res.FixedMean, res.RandomMean = all.mean[0], all.mean[1]
+	res.T = all.t()
+	res.MaxAbsT = math.Abs(res.T)
+
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	for _, p := range []float64{0.5, 0.75, 0.9, 0.95, 0.99} {
+		limit := sorted[int(p*float64(len(sorted)-1))]
+		t := welch(samples, classes, limit).t()
+		res.Crops = append(res.Crops, CropResult{Percentile: p * 100, T: t})
+		if math.Abs(t) > res.MaxAbsT {
+			res.MaxAbsT = math.Abs(t)
+		}
+	}
+	res.Leaky = res.MaxAbsT > Threshold
+	return res
+}
+
+type welchAcc struct {
+	n    [2]int
+	mean [2]float64
+	m2   [2]float64
+}
+
+// welch 以 Welford 在线算法累积两类样本中不超过 limit 的计时。
+func welch(samples []float64, classes []bool, limit float64) welchAcc {
+	var acc welchAcc
+	for i, x := range samples {
+		if x > limit {
+			continue
+		}
+		c := 0
+		if classes[i] {
+			c = 1
+		}
+		acc.n[c]++
+		delta := x - acc.mean[c]
+		acc.mean[c] += delta / float64(acc.n[c])
+		acc.m2[c] += delta * (x - acc.mean[c])
+	}
+	return acc
+}
+
+func (a welchAcc) t() float64 {
+	if a.n[0] < 2 || a.n[1] < 2 {
+		return 0
+	}
+	v0 := a.m2[0] / float64(a.n[0]-1)
+	v1 := a.m2[1] / float64(a.n[1]-1)
+	den := math.Sqrt(v0/float64(a.n[0]) + v1/float64(a.n[1]))
+	if den == 0 {
+		return 0
+	}
+	return (a.mean[0] - a.mean[1]) / den
+}
@@ -0,0 +1,61 @@
+package dudect
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestWelchExact(t *testing.T) {
+	// 固定类 {1,2,3}：均值 2、方差 1；随机类 {4,5,6,7}：均值 5.5、方差 5/3。
+	// t = (2-5.5)/√(1/3+5/12) = -7/√3。
+	samples := []float64{1, 4, 2, 5, 3, 6, 7}
+	classes := []bool{false, true, false, true, false, true, true}
+	acc := welch(samples, classes, math.Inf(1))
+	if acc.n != [2]int{3, 4} || acc.mean != [2]float64{2, 5.5} {
+		t.Fatalf("计数 %v、均值 %v", acc.n, acc.mean)
+	}
+	if got, want := acc.t(), -7/math.Sqrt(3); math.Abs(got-want) > 1e-12 {
+		t.Fatalf("t = %v，期望 %v", got, want)
+	}
+}
+
+func TestWelchCropExcludesClass(t *testing.T) {
+	// 固定类的样本全部大于裁剪上限，只剩一类时 t 为 0。
+	samples := []float64{10, 1, 11, 2, 12, 3}
+	classes := []bool{false, true, false, true, false, true}
+	acc := welch(samples, classes, 5)
+	if acc.n != [2]int{0, 3} || acc.t() != 0 {
+		t.Fatalf("计数 %v，t = %v", acc.n, acc.t())
+	}
+}
+
+// synthetic 生成两类交错的正态样本，随机类的均值比固定类大 shift。
+func synthetic(n int, shift float64) ([]float64, []bool) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	samples := make([]float64, n)
+	classes := make([]bool, n)
+	for i := range samples {
+		classes[i] = rng.IntN(2) == 1
+		samples[i] = 100 + 5*rng.NormFloat64()
+		if classes[i] {
+			samples[i] += shift
+		}
+	}
+	return samples, classes
+}
+
+func TestAnalyze(t *testing.T) {
+	same := analyze(synthetic(20000, 0))
+	if same.Leaky || same.MaxAbsT >= Threshold {
+		t.Fatalf("同分布样本被判为泄露: max|t| = %v", same.MaxAbsT)
+	}
+	if same.FixedCount+same.RandomCount != 20000 || len(same.Crops) != 5 {
+		t.Fatalf("计数 %d+%d，裁剪结果 %d 个", same.FixedCount, same.RandomCount, len(same.Crops))
+	}
+
+	shifted := analyze(synthetic(20000, 1))
+	if !shifted.Leaky || shifted.T > -Threshold {
+		t.Fatalf("均值相差 1 的样本应判为泄露: t = %v", shifted.T)
+	}
+}
@@ -0,0 +1,130 @@
+package fault
+
+import (
+	"fmt"
+
+	"S-AES/utils/saes"
+)
+
+// Progress 记录使用前 Pairs 个密文对后剩余的最后一轮轮密钥候选数。
+type Progress struct {
+	Pairs      int
+	Candidates int
+}
+
+// DFAResult 为差分故障分析的结果。
+type DFAResult struct {
+	LastRoundKeys []uint16
+	// MasterKeys 为由候选轮密钥逆推、并经正确密文验证后的主密钥。
+	MasterKeys []uint16
+	Progress   []Progress
+}
+
+// DFA 对最后一轮做差分故障分析。攻击者已知故障模型与注入位置（以及 cfg.Nibble、cfg.Bit 若已固定），
+// 但不知道具体的明文差分。注入位置与最后一次 SubNib 之间只能有线性步骤。
+func DFA(c *saes.Cipher, cfg Config, pairs []Pair) (*DFAResult, error) {
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("至少需要一个密文对")
+	}
+	diffs, err := reachableDiffs(c, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	alive := make([]bool, 1<<16)
+	for k := range alive {
+		alive[k] = true
+	}
+	remaining := len(alive)
+	res := &DFAResult{}
+	for i, p := range pairs {
+		if p.Correct != p.Faulty {
+			for k := range alive {
+				if !alive[k] {
+					continue
+				}
+				d := c.PeelLastRound(p.Correct, uint16(k)) ^ c.PeelLastRound(p.Faulty, uint16(k))
+				if !diffs[d] {
+					alive[k] = false
+					remaining--
+				}
+			}
+		}
+		res.Progress = append(res.Progress, Progress{Pairs: i + 1, Candidates: remaining})
+	}
+
+	for k, ok := range alive {
+		if !ok {
+			continue
+		}
+		res.LastRoundKeys = append(res.LastRoundKeys, uint16(k))
+		master, err := c.KeyFromRoundKey(uint16(k), c.Rounds())
+		if err != nil {
+			return nil, err
+		}
+		if verify(c, master, pairs) {
+			res.MasterKeys = append(res.MasterKeys, master)
+		}
+	}
+	return res, nil
+}
+
+// reachableDiffs 把故障模型允许的掩码经注入点之后的线性步骤传播到最后一次 SubNib 的输入。
+func reachableDiffs(c *saes.Cipher, cfg Config) (map[uint16]bool, error) {
+	points := c.Points()
+	idx := indexOf(points, cfg.Before)
+	target := indexOf(points, saes.Point{Round: c.Rounds(), Step: saes.StepSubNib})
+	if idx < 0 {
+		return nil, fmt.Errorf("该变体中不存在步骤 %s", cfg.Before)
+	}
+	if idx > target {
+		return nil, fmt.Errorf("DFA 需要在最后一次 SubNib 之前注入故障")
+	}
+	for _, p := range points[idx:target] {
+		if p.Step == saes.StepSubNib {
+			return nil, fmt.Errorf("注入位置与最后一次 SubNib 之间存在非线性步骤，DFA 不适用")
+		}
+	}
+
+	diffs := make(map[uint16]bool)
+	for _, mask := range faultMasks(cfg) {
+		d := mask
+		for _, p := range points[idx:target] {
+			switch p.Step {
+			case saes.StepShiftRows:
+				d = saes.ShiftRowsState(d)
+			case saes.StepMixColumns:
+				d = c.MixColumnsState(d)
+			}
+		}
+		diffs[d] = true
+	}
+	return diffs, nil
+}
+
+func faultMasks(cfg Config) []uint16 {
+	var masks []uint16
+	for nib := 0; nib < 4; nib++ {
+		if cfg.Nibble >= 0 && nib != cfg.Nibble {
+			continue
+		}
+		for v := uint16(1); v < 16; v++ {
+			if cfg.Kind == BitFlip {
+				if v&(v-1) != 0 || (cfg.Bit >= 0 && v != 1<<cfg.Bit) {
+					continue
+				}
+			}
+			masks = append(masks, v<<(12-4*nib))
+		}
+	}
+	return masks
+}
+
+func verify(c *saes.Cipher, key uint16, pairs []Pair) bool {
+	for _, p := range pairs {
+		if c.EncryptBlock(p.Plaintext, key) != p.Correct {
+			return false
+		}
+	}
+	return true
+}
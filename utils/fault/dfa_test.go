@@ -0,0 +1,48 @@
+package fault
+
+import (
+	"slices"
+	"testing"
+
+	"S-AES/utils/saes"
+)
+
+func TestDFARecoversMasterKey(t *testing.T) {
+	const key = 0xA73B
+	c := saes.StandardCipher()
+	for _, kind := range []Kind{BitFlip, NibbleRandom} {
+		cfg := Config{Key: key, Kind: kind, Before: DefaultLocation(c, kind), Nibble: -1, Bit: -1, Count: 32, Seed: 1}
+		pairs, err := Collect(c, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := DFA(c, cfg, pairs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Contains(res.MasterKeys, key) {
+			t.Fatalf("%s: 未恢复主密钥，候选 %v", kind, res.MasterKeys)
+		}
+		last := res.Progress[len(res.Progress)-1]
+		if last.Candidates != len(res.LastRoundKeys) || last.Candidates >= 1<<16 {
+			t.Fatalf("%s: 候选数 %d 未收敛", kind, last.Candidates)
+		}
+	}
+}
+
+func TestInjectAtStart(t *testing.T) {
+	c := saes.StandardCipher()
+	got, err := Inject(c, 0x1234, 0x2D55, saes.Point{Round: 0, Step: saes.StepAddRoundKey}, 0x0F00)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := c.EncryptBlock(0x1234^0x0F00, 0x2D55); got != want {
+		t.Fatalf("Inject = %04X，期望 %04X", got, want)
+	}
+	if _, err := Inject(c, 0, 0, saes.Point{Round: 9, Step: saes.StepSubNib}, 1); err == nil {
+		t.Fatal("不存在的步骤应报错")
+	}
+	if _, err := DFA(c, Config{}, nil); err == nil {
+		t.Fatal("没有密文对时应报错")
+	}
+}
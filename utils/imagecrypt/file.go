@@ -0,0 +1,165 @@
+package imagecrypt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image/png"
+
+	"S-AES/utils/saes"
+)
+
+// Format 为图像文件格式。
+type Format string
+
+const (
+	FormatBMP Format = "bmp"
+	FormatPNG Format = "png"
+)
+
+// ContentType 返回格式对应的 MIME 类型。
+func (f Format) ContentType() string {
+	if f == FormatBMP {
+		return "image/bmp"
+	}
+	return "image/png"
+}
+
+var pngMagic = []byte("\x89PNG\r\n\x1a\n")
+
+// ErrUnsupportedFormat 表示文件既不是 BMP 也不是 PNG。
+var ErrUnsupportedFormat = errors.New("仅支持未压缩的 BMP 与 PNG 图像")
+
+// Encrypt 按文件头识别 BMP 或 PNG 并加密其像素数据，输出同格式、同尺寸、可直接查看的图像。
+func Encrypt(data []byte, kb *saes.KeyedBlock, mode saes.Mode, iv uint16) ([]byte, Format, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte("BM")):
+		out, err := EncryptBMP(data, kb, mode, iv)
+		return out, FormatBMP, err
+	case bytes.HasPrefix(data, pngMagic):
+		out, err := EncryptPNG(data, kb, mode, iv)
+		return out, FormatPNG, err
+	default:
+		return nil, "", ErrUnsupportedFormat
+	}
+}
+
+// EncryptPNG 解码 PNG，用 EncryptPixels 加密 RGB 后重新编码为 RGBA PNG。
+// 辅助数据块不会保留，调色板图像输出为真彩色。
+func EncryptPNG(data []byte, kb *saes.KeyedBlock, mode saes.Mode, iv uint16) ([]byte, error) {
+	cfg, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("无法解析 PNG: %v", err)
+	}
+	if err := checkSize(cfg.Width, cfg.Height); err != nil {
+		return nil, err
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("无法解析 PNG: %v", err)
+	}
+	enc, err := EncryptPixels(img, kb, mode, iv)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, enc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// bmpHeader 为解析 BMP 所需的字段。
+type bmpHeader struct {
+	offset        int
+	width, height int
+	bitCount      int
+}
+
+// BMP 压缩方式：只接受像素按行原样存放的 BI_RGB、BI_BITFIELDS 与 BI_ALPHABITFIELDS。
+const (
+	biRGB            = 0
+	biBitfields      = 3
+	biAlphaBitfields = 6
+)
+
+// EncryptBMP 原样保留文件头、信息头、调色板与行尾对齐字节，只加密各行的像素字节。
+// 各行按文件中的顺序（通常自下而上）拼接为一条字节流加密，因此链式模式跨行延续。
+func EncryptBMP(data []byte, kb *saes.KeyedBlock, mode saes.Mode, iv uint16) ([]byte, error) {
+	h, err := parseBMP(data)
+	if err != nil {
+		return nil, err
+	}
+	rowBytes := (h.width*h.bitCount + 7) / 8
+	stride := (h.width*h.bitCount + 31) / 32 * 4
+
+	pixels := make([]byte, 0, rowBytes*h.height)
+	for y := 0; y < h.height; y++ {
+		start := h.offset + y*stride
+		pixels = append(pixels, data[start:start+rowBytes]...)
+	}
+	if err := encryptInPlace(kb, mode, iv, pixels); err != nil {
+		return nil, err
+	}
+	out := bytes.Clone(data)
+	for y := 0; y < h.height; y++ {
+		start := h.offset + y*stride
+		copy(out[start:start+rowBytes], pixels[y*rowBytes:(y+1)*rowBytes])
+	}
+	return out, nil
+}
+
+func parseBMP(data []byte) (bmpHeader, error) {
+	if len(data) < 26 || !bytes.HasPrefix(data, []byte("BM")) {
+		return bmpHeader{}, errors.New("无法解析 BMP: 文件头不完整")
+	}
+	le := binary.LittleEndian
+	h := bmpHeader{offset: int(le.Uint32(data[10:]))}
+	dibSize := int(le.Uint32(data[14:]))
+	switch {
+	case dibSize == 12:
+		// BITMAPCOREHEADER：16 位宽高，无压缩字段。
+		h.width = int(int16(le.Uint16(data[18:])))
+		h.height = int(int16(le.Uint16(data[20:])))
+		h.bitCount = int(le.Uint16(data[24:]))
+	case dibSize >= 40 && len(data) >= 14+40:
+		h.width = int(int32(le.Uint32(data[18:])))
+		h.height = int(int32(le.Uint32(data[22:])))
+		h.bitCount = int(le.Uint16(data[28:]))
+		switch compression := le.Uint32(data[30:]); compression {
+		case biRGB, biBitfields, biAlphaBitfields:
+		default:
+			return bmpHeader{}, fmt.Errorf("不支持压缩的 BMP（压缩方式 %d）", compression)
+		}
+	default:
+		return bmpHeader{}, fmt.Errorf("无法解析 BMP: 未知的信息头长度 %d", dibSize)
+	}
+	if h.height < 0 {
+		h.height = -h.height // 负高度表示自上而下存放
+	}
+
+	switch h.bitCount {
+	case 1, 4, 8, 16, 24, 32:
+	default:
+		return bmpHeader{}, fmt.Errorf("不支持的 BMP 位深 %d", h.bitCount)
+	}
+	if err := checkSize(h.width, h.height); err != nil {
+		return bmpHeader{}, err
+	}
+	stride := (h.width*h.bitCount + 31) / 32 * 4
+	if h.offset < 14+dibSize || h.offset > len(data) || len(data)-h.offset < stride*h.height {
+		return bmpHeader{}, errors.New("无法解析 BMP: 像素数据被截断")
+	}
+	return h, nil
+}
+
+func checkSize(width, height int) error {
+	if width <= 0 || height <= 0 {
+		return errors.New("图像尺寸无效")
+	}
+	if width > MaxPixels/height {
+		return fmt.Errorf("图像过大（至多 %d 像素）", MaxPixels)
+	}
+	return nil
+}
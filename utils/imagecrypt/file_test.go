@@ -0,0 +1,112 @@
+package imagecrypt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"testing"
+
+	"S-AES/utils/cipherstats"
+	"S-AES/utils/saes"
+)
+
+// bmp24 把图像写成自下而上存放的 24 位 BI_RGB BMP。
+func bmp24(img *image.NRGBA) []byte {
+	b := img.Bounds()
+	stride := (b.Dx()*3 + 3) &^ 3
+	size := 54 + stride*b.Dy()
+	out := make([]byte, size)
+	le := binary.LittleEndian
+	copy(out, "BM")
+	le.PutUint32(out[2:], uint32(size))
+	le.PutUint32(out[10:], 54)
+	le.PutUint32(out[14:], 40)
+	le.PutUint32(out[18:], uint32(b.Dx()))
+	le.PutUint32(out[22:], uint32(b.Dy()))
+	le.PutUint16(out[26:], 1)
+	le.PutUint16(out[28:], 24)
+	for y := 0; y < b.Dy(); y++ {
+		row := out[54+(b.Dy()-1-y)*stride:]
+		for x := 0; x < b.Dx(); x++ {
+			c := img.NRGBAAt(x, y)
+			row[3*x], row[3*x+1], row[3*x+2] = c.B, c.G, c.R
+		}
+		for i := b.Dx() * 3; i < stride; i++ {
+			row[i] = 0xEE // 便于检查对齐字节未被改动
+		}
+	}
+	return out
+}
+
+func TestEncryptBMP(t *testing.T) {
+	img, _ := Penguin(62) // 每行 186 字节，对齐到 188
+	data := bmp24(img)
+	kb, _ := saes.NewKeyedBlock(saes.Standard, "0x2D55")
+
+	out, format, err := Encrypt(data, kb, saes.ModeECB, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != FormatBMP || len(out) != len(data) || !bytes.Equal(out[:54], data[:54]) {
+		t.Fatal("BMP 文件头或长度被改动")
+	}
+	stride, rowBytes := 188, 186
+	pixels := make([]byte, 0, rowBytes*img.Bounds().Dy())
+	for y := 0; y < img.Bounds().Dy(); y++ {
+		row := out[54+y*stride : 54+(y+1)*stride]
+		if row[186] != 0xEE || row[187] != 0xEE {
+			t.Fatalf("第 %d 行的对齐字节被改动", y)
+		}
+		pixels = append(pixels, row[:rowBytes]...)
+	}
+	if r, _ := cipherstats.Analyze(pixels, 0); r.Mode != cipherstats.ModeECB {
+		t.Fatalf("ECB 加密的 BMP 应保留图案: %s", r.Reason)
+	}
+
+	// 同一密钥下 CTR 加密两次即解密，验证像素字节按原顺序写回。
+	ctr, _, _ := Encrypt(data, kb, saes.ModeCTR, 0x1234)
+	back, _, _ := Encrypt(ctr, kb, saes.ModeCTR, 0x1234)
+	if !bytes.Equal(back, data) || bytes.Equal(ctr, data) {
+		t.Fatal("CTR 两次加密应还原原文件")
+	}
+
+	compressed := bytes.Clone(data)
+	binary.LittleEndian.PutUint32(compressed[30:], 1)
+	if _, _, err := Encrypt(compressed, kb, saes.ModeECB, 0); err == nil {
+		t.Fatal("RLE 压缩的 BMP 应被拒绝")
+	}
+	if _, _, err := Encrypt(data[:1000], kb, saes.ModeECB, 0); err == nil {
+		t.Fatal("截断的 BMP 应被拒绝")
+	}
+	if _, _, err := Encrypt([]byte("GIF89a"), kb, saes.ModeECB, 0); err != ErrUnsupportedFormat {
+		t.Fatalf("未知格式应返回 ErrUnsupportedFormat，得到 %v", err)
+	}
+}
+
+func TestEncryptPNG(t *testing.T) {
+	img, _ := Penguin(40)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	kb, _ := saes.NewKeyedBlock(saes.Standard, "0x2D55")
+	out, format, err := Encrypt(buf.Bytes(), kb, saes.ModeCBC, 0x1234)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != FormatPNG {
+		t.Fatalf("格式为 %s", format)
+	}
+	dec, err := png.Decode(bytes.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dec.Bounds() != img.Bounds() {
+		t.Fatalf("尺寸由 %v 变为 %v", img.Bounds(), dec.Bounds())
+	}
+	want, _ := EncryptPixels(img, kb, saes.ModeCBC, 0x1234)
+	if !bytes.Equal(toNRGBA(dec).Pix, want.Pix) {
+		t.Fatal("PNG 像素与 EncryptPixels 结果不一致")
+	}
+}
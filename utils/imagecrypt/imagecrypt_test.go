@@ -0,0 +1,58 @@
+package imagecrypt
+
+import (
+	"bytes"
+	"testing"
+
+	"S-AES/utils/cipherstats"
+	"S-AES/utils/saes"
+)
+
+func opaque(t *testing.T, pix []byte) {
+	t.Helper()
+	for i := 3; i < len(pix); i += 4 {
+		if pix[i] != 0xFF {
+			t.Fatal("alpha 通道被修改")
+		}
+	}
+}
+
+func TestPenguinECBLeaksPattern(t *testing.T) {
+	img, err := Penguin(64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kb, _ := saes.NewKeyedBlock(saes.Standard, "0x2D55")
+	ecb, err := EncryptPixels(img, kb, saes.ModeECB, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cbc, err := EncryptPixels(img, kb, saes.ModeCBC, 0x1234)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ecb.Bounds() != img.Bounds() || cbc.Bounds() != img.Bounds() {
+		t.Fatal("加密后尺寸改变")
+	}
+
+	opaque(t, ecb.Pix)
+	opaque(t, cbc.Pix)
+	ecbStats, _ := cipherstats.Analyze(RGB(ecb), 0)
+	cbcStats, _ := cipherstats.Analyze(RGB(cbc), 0)
+	if ecbStats.Mode != cipherstats.ModeECB || cbcStats.Mode != cipherstats.ModeChained {
+		t.Fatalf("ECB 判为 %s，CBC 判为 %s", ecbStats.Mode, cbcStats.Mode)
+	}
+
+	// 同色像素对在 ECB 下得到相同密文：左上角的天空区域第 0 与第 2 个像素相同。
+	if !bytes.Equal(ecb.Pix[0:4], ecb.Pix[8:12]) {
+		t.Fatal("ECB 下相同的天空像素应得到相同密文")
+	}
+
+	side := SideBySide(4, img, ecb, cbc)
+	if side.Bounds().Dx() != 3*64+2*4 || side.Bounds().Dy() != img.Bounds().Dy() {
+		t.Fatalf("拼接尺寸为 %v", side.Bounds())
+	}
+	if _, err := Penguin(8); err == nil {
+		t.Fatal("过小的宽度应被拒绝")
+	}
+}
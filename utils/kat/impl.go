@@ -0,0 +1,227 @@
+package kat
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"S-AES/utils"
+	"S-AES/utils/saes"
+)
+
+// ErrUnsupported 表示被测实现不支持某个操作，该用例记为跳过而非失败。
+var ErrUnsupported = errors.New("不支持的操作")
+
+// unsupportedExitCode 为命令行约定中表示“不支持”的退出码。
+const unsupportedExitCode = 2
+
+// Implementation 为被测实现：执行一个约定操作并返回其文本输出。
+type Implementation interface {
+	Do(op string, args []string) (string, error)
+}
+
+// ImplementationFunc 把普通函数适配为 Implementation。
+type ImplementationFunc func(op string, args []string) (string, error)
+
+// Do 实现 Implementation。
+func (f ImplementationFunc) Do(op string, args []string) (string, error) {
+	return f(op, args)
+}
+
+// Reference 以本仓库的 saes 包实现全部约定操作，bc 为使用的分组实现。
+func Reference(bc saes.BlockCipher) Implementation {
+	return ImplementationFunc(func(op string, args []string) (string, error) {
+		switch op {
+		case OpBlockEncrypt, OpBlockDecrypt:
+			if len(args) != 2 {
+				return "", fmt.Errorf("%s 需要 2 个参数", op)
+			}
+			block, err := utils.ParseBlockString(args[1])
+			if err != nil {
+				return "", err
+			}
+			var out string
+			if op == OpBlockEncrypt {
+				out, err = saes.EncryptBinaryWith(bc, fmt.Sprintf("%016b", block), args[0])
+			} else {
+				out, err = saes.DecryptBinaryWith(bc, fmt.Sprintf("%016b", block), args[0])
+			}
+			if err != nil {
+				return "", err
+			}
+			value, err := utils.ParseBlockString(out)
+			if err != nil {
+				return "", err
+			}
+			return utils.FormatHex16(value), nil
+		case OpExpandKey:
+			if len(args) != 1 {
+				return "", fmt.Errorf("%s 需要 1 个参数", op)
+			}
+			key, err := utils.ParseBlockString(args[0])
+			if err != nil {
+				return "", err
+			}
+			c, ok := bc.(*saes.Cipher)
+			if !ok {
+				c = saes.StandardCipher()
+			}
+			keys := c.RoundKeys(key)
+			parts := make([]string, len(keys))
+			for i, k := range keys {
+				parts[i] = utils.FormatHex16(k)
+			}
+			return strings.Join(parts, " "), nil
+		case OpEncrypt, OpDecrypt:
+			if len(args) != 5 {
+				return "", fmt.Errorf("%s 需要 5 个参数", op)
+			}
+			return referenceMode(bc, op, args[0], args[1], args[2], args[3], args[4])
+		default:
+			return "", ErrUnsupported
+		}
+	})
+}
+
+// referenceMode 使用 saes 的字节级工作模式接口实现 encrypt/decrypt 操作。
+func referenceMode(bc saes.BlockCipher, op, modeName, paddingName, key, ivText, dataHex string) (string, error) {
+	mode, err := saes.ParseMode(modeName)
+	if err != nil {
+		return "", ErrUnsupported
+	}
+	padding, err := saes.ParsePadding(paddingName)
+	if err != nil {
+		return "", ErrUnsupported
+	}
+	kb, err := saes.NewKeyedBlock(bc, key)
+	if err != nil {
+		return "", err
+	}
+	var iv uint16
+	if ivText != NoIV {
+		if iv, err = utils.ParseBlockString(ivText); err != nil {
+			return "", fmt.Errorf("无法解析初始向量: %w", err)
+		}
+	}
+	data, err := hex.DecodeString(dataHex)
+	if err != nil {
+		return "", fmt.Errorf("无法解析十六进制数据: %w", err)
+	}
+
+	var out []byte
+	if op == OpEncrypt {
+		out, err = saes.EncryptBytes(kb, mode, padding, iv, data)
+	} else {
+		out, err = saes.DecryptBytes(kb, mode, padding, iv, data)
+	}
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(out), nil
+}
+
+// Command 按命令行约定调用外部程序：每个用例执行一次 `name [extra...] OP ARGS...`，
+// 标准输出的首行为结果；退出码 2 表示不支持该操作。
+func Command(name string, extra []string, timeout time.Duration) Implementation {
+	return ImplementationFunc(func(op string, args []string) (string, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		argv := append(append(append([]string(nil), extra...), op), args...)
+		cmd := exec.CommandContext(ctx, name, argv...)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) && exitErr.ExitCode() == unsupportedExitCode {
+				return "", ErrUnsupported
+			}
+			return "", fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		line, _, _ := strings.Cut(stdout.String(), "\n")
+		return line, nil
+	})
+}
+
+// HTTPRequest 与 HTTPResponse 为 HTTP 约定的请求与响应体。
+type HTTPRequest struct {
+	Op   string   `json:"op"`
+	Args []string `json:"args"`
+}
+
+type HTTPResponse struct {
+	Result      string `json:"result"`
+	Error       string `json:"error,omitempty"`
+	Unsupported bool   `json:"unsupported,omitempty"`
+}
+
+// HTTP 按 HTTP 约定调用外部服务：向 url POST 一个 HTTPRequest，期望返回 HTTPResponse。
+func HTTP(url string, timeout time.Duration) Implementation {
+	client := &http.Client{Timeout: timeout}
+	return ImplementationFunc(func(op string, args []string) (string, error) {
+		body, err := json.Marshal(HTTPRequest{Op: op, Args: args})
+		if err != nil {
+			return "", err
+		}
+		resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if err != nil {
+			return "", err
+		}
+		var out HTTPResponse
+		if err := json.Unmarshal(data, &out); err != nil {
+			return "", fmt.Errorf("HTTP %d，响应不是合法 JSON: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+		}
+		switch {
+		case out.Unsupported || resp.StatusCode == http.StatusNotImplemented:
+			return "", ErrUnsupported
+		case out.Error != "":
+			return "", errors.New(out.Error)
+		case resp.StatusCode != http.StatusOK:
+			return "", fmt.Errorf("HTTP %d", resp.StatusCode)
+		}
+		return out.Result, nil
+	})
+}
+
+// Handler 以 HTTP 约定对外提供 impl，可作为其他实现对照的参考服务。
+func Handler(impl Implementation) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			_ = json.NewEncoder(w).Encode(HTTPResponse{Error: "仅支持 POST"})
+			return
+		}
+		var req HTTPRequest
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(HTTPResponse{Error: err.Error()})
+			return
+		}
+		result, err := impl.Do(req.Op, req.Args)
+		switch {
+		case errors.Is(err, ErrUnsupported):
+			w.WriteHeader(http.StatusNotImplemented)
+			_ = json.NewEncoder(w).Encode(HTTPResponse{Unsupported: true, Error: err.Error()})
+		case err != nil:
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(HTTPResponse{Error: err.Error()})
+		default:
+			_ = json.NewEncoder(w).Encode(HTTPResponse{Result: result})
+		}
+	})
+}
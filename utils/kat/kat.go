@@ -0,0 +1,123 @@
+// Package kat 提供 S-AES 的已知答案测试（KAT）向量，以及按统一的命令行/HTTP 约定校验任意实现的工具。
+package kat
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+//go:embed vectors.json
+var vectorsJSON []byte
+
+// BlockVector 为单个分组的加解密向量；Key 为 16、32 或 48 位十六进制密钥（后两者为二重/三重级联）。
+type BlockVector struct {
+	Name       string `json:"name"`
+	Key        string `json:"key"`
+	Plaintext  string `json:"plaintext"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// KeyExpansionVector 记录一个密钥展开出的各轮轮密钥（第 0 个为主密钥本身）。
+type KeyExpansionVector struct {
+	Name      string   `json:"name"`
+	Key       string   `json:"key"`
+	RoundKeys []string `json:"round_keys"`
+}
+
+// ModeVector 为工作模式向量；明文与密文均以十六进制字节串给出，IV 为空表示该模式不使用初始向量。
+type ModeVector struct {
+	Name          string `json:"name"`
+	Mode          string `json:"mode"`
+	Padding       string `json:"padding"`
+	Key           string `json:"key"`
+	IV            string `json:"iv,omitempty"`
+	PlaintextHex  string `json:"plaintext_hex"`
+	CiphertextHex string `json:"ciphertext_hex"`
+}
+
+// Vectors 为完整的测试向量集合。
+type Vectors struct {
+	Version      int                  `json:"version"`
+	Description  string               `json:"description"`
+	Block        []BlockVector        `json:"block"`
+	KeyExpansion []KeyExpansionVector `json:"key_expansion"`
+	Cascade      []BlockVector        `json:"cascade"`
+	Modes        []ModeVector         `json:"modes"`
+}
+
+// Load 返回内置的测试向量。
+func Load() (*Vectors, error) {
+	return Parse(vectorsJSON)
+}
+
+// Raw 返回内置向量文件的原始内容。
+func Raw() []byte {
+	return append([]byte(nil), vectorsJSON...)
+}
+
+// Parse 解析 JSON 格式的测试向量。
+func Parse(data []byte) (*Vectors, error) {
+	var v Vectors
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("解析测试向量失败: %w", err)
+	}
+	return &v, nil
+}
+
+// 约定中的操作名。每个操作的参数与期望输出见 Cases。
+const (
+	OpBlockEncrypt = "block-encrypt" // KEY PLAINTEXT -> CIPHERTEXT
+	OpBlockDecrypt = "block-decrypt" // KEY CIPHERTEXT -> PLAINTEXT
+	OpExpandKey    = "expand-key"    // KEY -> 以空格分隔的轮密钥
+	OpEncrypt      = "encrypt"       // MODE PADDING KEY IV PLAINTEXT_HEX -> CIPHERTEXT_HEX
+	OpDecrypt      = "decrypt"       // MODE PADDING KEY IV CIPHERTEXT_HEX -> PLAINTEXT_HEX
+)
+
+// NoIV 为不使用初始向量的模式在约定中的 IV 占位符。
+const NoIV = "-"
+
+// Case 为一次约定调用及其期望输出。
+type Case struct {
+	Name     string
+	Op       string
+	Args     []string
+	Expected string
+}
+
+// Cases 把全部向量展开为双向的约定调用。
+func (v *Vectors) Cases() []Case {
+	var cases []Case
+	blocks := func(group string, vectors []BlockVector) {
+		for _, b := range vectors {
+			name := group + "/" + b.Name
+			cases = append(cases,
+				Case{Name: name + "/encrypt", Op: OpBlockEncrypt, Args: []string{b.Key, b.Plaintext}, Expected: b.Ciphertext},
+				Case{Name: name + "/decrypt", Op: OpBlockDecrypt, Args: []string{b.Key, b.Ciphertext}, Expected: b.Plaintext},
+			)
+		}
+	}
+	blocks("block", v.Block)
+	for _, k := range v.KeyExpansion {
+		cases = append(cases, Case{
+			Name:     "key_expansion/" + k.Name,
+			Op:       OpExpandKey,
+			Args:     []string{k.Key},
+			Expected: strings.Join(k.RoundKeys, " "),
+		})
+	}
+	blocks("cascade", v.Cascade)
+	for _, m := range v.Modes {
+		iv := m.IV
+		if iv == "" {
+			iv = NoIV
+		}
+		name := "modes/" + m.Name
+		cases = append(cases,
+			Case{Name: name + "/encrypt", Op: OpEncrypt, Args: []string{m.Mode, m.Padding, m.Key, iv, m.PlaintextHex}, Expected: m.CiphertextHex},
+			Case{Name: name + "/decrypt", Op: OpDecrypt, Args: []string{m.Mode, m.Padding, m.Key, iv, m.CiphertextHex}, Expected: m.PlaintextHex},
+		)
+	}
+	return cases
+}
@@ -0,0 +1,144 @@
+package kat
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"S-AES/utils"
+	"S-AES/utils/saes"
+)
+
+func mustLoad(t *testing.T) *Vectors {
+	t.Helper()
+	v, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(v.Block) == 0 || len(v.KeyExpansion) == 0 || len(v.Cascade) == 0 || len(v.Modes) == 0 {
+		t.Fatal("内置测试向量不完整")
+	}
+	return v
+}
+
+func toBinary(t *testing.T, s string) string {
+	t.Helper()
+	v, err := utils.ParseBlockString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fmt.Sprintf("%016b", v)
+}
+
+func TestEncryptBinary(t *testing.T) {
+	v := mustLoad(t)
+	for _, b := range append(v.Block, v.Cascade...) {
+		pt, ct := toBinary(t, b.Plaintext), toBinary(t, b.Ciphertext)
+		got, err := saes.EncryptBinary(pt, b.Key)
+		if err != nil || got != ct {
+			t.Errorf("%s: EncryptBinary = %s, %v；期望 %s", b.Name, got, err, ct)
+		}
+		got, err = saes.DecryptBinary(ct, b.Key)
+		if err != nil || got != pt {
+			t.Errorf("%s: DecryptBinary = %s, %v；期望 %s", b.Name, got, err, pt)
+		}
+	}
+}
+
+func TestEncryptASCIIToBase64(t *testing.T) {
+	// ASCII/Base64 接口固定使用零填充，其余填充方式由 TestReferenceEngines 经字节级接口覆盖。
+	for _, m := range mustLoad(t).Modes {
+		if m.Padding != string(saes.PaddingZero) {
+			continue
+		}
+		pt, ct := decodeMode(t, m)
+		switch m.Mode {
+		case "ecb":
+			got, err := saes.EncryptASCIIToBase64(pt, m.Key)
+			if err != nil || got != ct {
+				t.Errorf("%s: EncryptASCIIToBase64 = %q, %v；期望 %q", m.Name, got, err, ct)
+			}
+			back, err := saes.DecryptBase64ToASCII(ct, m.Key)
+			if err != nil || back != pt {
+				t.Errorf("%s: DecryptBase64ToASCII = %q, %v；期望 %q", m.Name, back, err, pt)
+			}
+		case "cbc":
+			got, err := saes.EncryptASCIIToBase64CBCWithIV(saes.Standard, pt, m.Key, m.IV)
+			if err != nil || got != ct {
+				t.Errorf("%s: EncryptASCIIToBase64CBCWithIV = %q, %v；期望 %q", m.Name, got, err, ct)
+			}
+			back, err := saes.DecryptBase64ToASCIICBC(ct, m.Key, m.IV)
+			if err != nil || back != pt {
+				t.Errorf("%s: DecryptBase64ToASCIICBC = %q, %v；期望 %q", m.Name, back, err, pt)
+			}
+		}
+	}
+}
+
+func TestCBCRandomIVRoundTrip(t *testing.T) {
+	for _, m := range mustLoad(t).Modes {
+		if m.Mode != "cbc" || m.Padding != string(saes.PaddingZero) {
+			continue
+		}
+		pt, _ := decodeMode(t, m)
+		ct, iv, err := saes.EncryptASCIIToBase64CBC(pt, m.Key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := saes.EncryptASCIIToBase64CBCWithIV(saes.Standard, pt, m.Key, iv)
+		if err != nil || want != ct {
+			t.Errorf("%s: 随机 IV %s 下的密文与 WithIV 结果不一致", m.Name, iv)
+		}
+	}
+}
+
+func TestReferenceEngines(t *testing.T) {
+	cases := mustLoad(t).Cases()
+	engines := map[string]saes.BlockCipher{
+		"reference":     saes.Standard,
+		"generic":       saes.StandardCipher(),
+		"fast":          saes.Fast,
+		"bitslice":      saes.Bitsliced,
+		"constant-time": saes.ConstantTime,
+	}
+	for name, bc := range engines {
+		report := Run(Reference(bc), cases)
+		if !report.OK() || report.Skipped != 0 {
+			t.Errorf("%s: 通过 %d/%d，跳过 %d", name, report.Passed, report.Total, report.Skipped)
+			for _, f := range report.Failures {
+				t.Logf("  %s: 得到 %q (%v)，期望 %q", f.Case.Name, f.Actual, f.Err, f.Case.Expected)
+			}
+		}
+	}
+}
+
+func TestHTTPContract(t *testing.T) {
+	srv := httptest.NewServer(Handler(Reference(saes.Standard)))
+	defer srv.Close()
+
+	report := Run(HTTP(srv.URL, 5*time.Second), mustLoad(t).Cases())
+	if !report.OK() || report.Passed != report.Total {
+		t.Fatalf("通过 %d/%d，失败 %d", report.Passed, report.Total, len(report.Failures))
+	}
+
+	report = Run(HTTP(srv.URL, 5*time.Second), []Case{{Name: "unknown", Op: "sign"}})
+	if report.Skipped != 1 {
+		t.Fatalf("未知操作应记为跳过，得到 %+v", report)
+	}
+}
+
+func decodeMode(t *testing.T, m ModeVector) (plaintext, ciphertextBase64 string) {
+	t.Helper()
+	pt, err := hex.DecodeString(m.PlaintextHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct, err := hex.DecodeString(m.CiphertextHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pt), base64.StdEncoding.EncodeToString(ct)
+}
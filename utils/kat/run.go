@@ -0,0 +1,53 @@
+package kat
+
+import (
+	"errors"
+	"strings"
+)
+
+// Failure 记录一个未通过的用例。
+type Failure struct {
+	Case   Case
+	Actual string
+	Err    error
+}
+
+// Report 汇总一次校验的结果。
+type Report struct {
+	Total    int
+	Passed   int
+	Skipped  int
+	Failures []Failure
+}
+
+// OK 在没有失败用例时返回 true。
+func (r *Report) OK() bool {
+	return len(r.Failures) == 0
+}
+
+// Run 依次执行 cases，输出比较时忽略首尾空白、大小写与 0x 前缀。
+func Run(impl Implementation, cases []Case) *Report {
+	report := &Report{Total: len(cases)}
+	for _, c := range cases {
+		actual, err := impl.Do(c.Op, c.Args)
+		switch {
+		case errors.Is(err, ErrUnsupported):
+			report.Skipped++
+		case err != nil:
+			report.Failures = append(report.Failures, Failure{Case: c, Err: err})
+		case normalize(actual) != normalize(c.Expected):
+			report.Failures = append(report.Failures, Failure{Case: c, Actual: actual})
+		default:
+			report.Passed++
+		}
+	}
+	return report
+}
+
+func normalize(s string) string {
+	fields := strings.Fields(strings.ToLower(s))
+	for i, f := range fields {
+		fields[i] = strings.TrimPrefix(f, "0x")
+	}
+	return strings.Join(fields, " ")
+}
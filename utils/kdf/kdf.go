@@ -0,0 +1,205 @@
+// Package kdf 由口令与盐派生 16/32/48 位 S-AES 密钥，支持 PBKDF2、scrypt 与 Argon2id。
+//
+// 派生出的密钥最多只有 48 位，无论 KDF 多慢，攻击者都可以绕开口令直接穷举密钥空间，
+// Crack 用现有的穷举与中间相遇攻击演示这一点。
+package kdf
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Algorithm 为 KDF 名称。
+type Algorithm string
+
+const (
+	// PBKDF2 使用 HMAC-SHA256。
+	PBKDF2   Algorithm = "pbkdf2"
+	Scrypt   Algorithm = "scrypt"
+	Argon2id Algorithm = "argon2id"
+)
+
+// Algorithms 列出全部支持的 KDF。
+var Algorithms = []Algorithm{PBKDF2, Scrypt, Argon2id}
+
+// SaltSize 为 NewSalt 生成的盐长度（字节）。
+const SaltSize = 16
+
+// 参数上限，防止单个请求耗尽 CPU 或内存。
+const (
+	maxIterations = 10_000_000
+	maxScryptN    = 1 << 20
+	maxMemoryKiB  = 256 * 1024
+	maxParallel   = 16
+)
+
+// Params 为 KDF 参数，零值字段由 DefaultParams 的对应值补齐。
+type Params struct {
+	Algorithm Algorithm
+	// Iterations 为 PBKDF2 的迭代次数，或 Argon2id 的 time 参数。
+	Iterations int
+	// N 与 R 为 scrypt 的 CPU/内存代价与块大小，N 须为 2 的幂。
+	N int
+	R int
+	// Memory 为 Argon2id 的内存用量（KiB）。
+	Memory uint32
+	// Parallelism 为 scrypt 的 p，或 Argon2id 的线程数。
+	Parallelism int
+}
+
+// DefaultParams 返回 alg 的默认参数。
+func DefaultParams(alg Algorithm) Params {
+	switch alg {
+	case Scrypt:
+		return Params{Algorithm: Scrypt, N: 1 << 15, R: 8, Parallelism: 1}
+	case Argon2id:
+		return Params{Algorithm: Argon2id, Iterations: 1, Memory: 64 * 1024, Parallelism: 4}
+	default:
+		return Params{Algorithm: PBKDF2, Iterations: 100_000}
+	}
+}
+
+// ParseAlgorithm 解析 KDF 名称（不区分大小写）；空字符串为 PBKDF2。
+func ParseAlgorithm(s string) (Algorithm, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "":
+		return PBKDF2, nil
+	case "argon2":
+		return Argon2id, nil
+	}
+	for _, alg := range Algorithms {
+		if s == string(alg) {
+			return alg, nil
+		}
+	}
+	return "", fmt.Errorf("不支持的 KDF: %s（可选 pbkdf2、scrypt、argon2id）", s)
+}
+
+// Resolve 补齐零值参数并检查取值范围。
+func (p Params) Resolve() (Params, error) {
+	alg, err := ParseAlgorithm(string(p.Algorithm))
+	if err != nil {
+		return p, err
+	}
+	def := DefaultParams(alg)
+	p.Algorithm = alg
+	if p.Iterations == 0 {
+		p.Iterations = def.Iterations
+	}
+	if p.N == 0 {
+		p.N = def.N
+	}
+	if p.R == 0 {
+		p.R = def.R
+	}
+	if p.Memory == 0 {
+		p.Memory = def.Memory
+	}
+	if p.Parallelism == 0 {
+		p.Parallelism = def.Parallelism
+	}
+
+	switch alg {
+	case PBKDF2:
+		if p.Iterations < 1 || p.Iterations > maxIterations {
+			return p, fmt.Errorf("PBKDF2 迭代次数必须在 1 到 %d 之间", maxIterations)
+		}
+	case Scrypt:
+		if p.N < 2 || p.N > maxScryptN || p.N&(p.N-1) != 0 {
+			return p, fmt.Errorf("scrypt 的 N 必须是 2 到 %d 之间的 2 的幂", maxScryptN)
+		}
+		if p.R < 1 || p.Parallelism < 1 || p.Parallelism > maxParallel || p.R*p.Parallelism >= 1<<30 {
+			return p, fmt.Errorf("scrypt 的 r、p 参数无效")
+		}
+		// scrypt 占用约 128·N·r·p 字节，与 Argon2id 使用同一内存上限。
+		if int64(p.N)*int64(p.R)*int64(p.Parallelism) > maxMemoryKiB*1024/128 {
+			return p, fmt.Errorf("scrypt 的内存用量 128·N·r·p 不能超过 %d MiB", maxMemoryKiB/1024)
+		}
+	case Argon2id:
+		if p.Iterations < 1 || p.Iterations > 64 {
+			return p, fmt.Errorf("Argon2id 的 time 参数必须在 1 到 64 之间")
+		}
+		if p.Memory < 8 || p.Memory > maxMemoryKiB {
+			return p, fmt.Errorf("Argon2id 内存必须在 8 到 %d KiB 之间", maxMemoryKiB)
+		}
+		if p.Parallelism < 1 || p.Parallelism > maxParallel {
+			return p, fmt.Errorf("Argon2id 线程数必须在 1 到 %d 之间", maxParallel)
+		}
+	}
+	return p, nil
+}
+
+// Derive 由 password 与 salt 派生 bits 位（16、32 或 48）密钥材料。
+func Derive(password, salt []byte, bits int, p Params) ([]byte, error) {
+	if bits != 16 && bits != 32 && bits != 48 {
+		return nil, fmt.Errorf("派生密钥长度必须是 16、32 或 48 位")
+	}
+	if len(password) == 0 {
+		return nil, fmt.Errorf("口令不能为空")
+	}
+	if len(salt) == 0 {
+		return nil, fmt.Errorf("盐不能为空")
+	}
+	p, err := p.Resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	n := bits / 8
+	switch p.Algorithm {
+	case Scrypt:
+		key, err := scrypt.Key(password, salt, p.N, p.R, p.Parallelism, n)
+		if err != nil {
+			return nil, fmt.Errorf("scrypt 派生失败: %w", err)
+		}
+		return key, nil
+	case Argon2id:
+		return argon2.IDKey(password, salt, uint32(p.Iterations), p.Memory, uint8(p.Parallelism), uint32(n)), nil
+	default:
+		return pbkdf2.Key(password, salt, p.Iterations, n, sha256.New), nil
+	}
+}
+
+// DeriveKey 与 Derive 相同，但返回可直接用于各加解密接口的 0x 十六进制密钥字符串。
+func DeriveKey(password, salt []byte, bits int, p Params) (string, error) {
+	key, err := Derive(password, salt, bits, p)
+	if err != nil {
+		return "", err
+	}
+	return FormatKey(key), nil
+}
+
+// FormatKey 把密钥材料格式化为 0x 开头的大写十六进制。
+func FormatKey(key []byte) string {
+	return "0x" + strings.ToUpper(hex.EncodeToString(key))
+}
+
+// ParseSalt 解析盐：0x 开头按十六进制解码，否则取 UTF-8 字节。
+func ParseSalt(s string) ([]byte, error) {
+	trimmed := strings.TrimSpace(s)
+	if strings.HasPrefix(strings.ToLower(trimmed), "0x") {
+		salt, err := hex.DecodeString(trimmed[2:])
+		if err != nil {
+			return nil, fmt.Errorf("无法解析十六进制盐: %w", err)
+		}
+		return salt, nil
+	}
+	return []byte(s), nil
+}
+
+// NewSalt 生成 SaltSize 字节的随机盐。
+func NewSalt() ([]byte, error) {
+	salt := make([]byte, SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("生成盐失败: %w", err)
+	}
+	return salt, nil
+}
@@ -0,0 +1,101 @@
+package kdf
+
+import (
+	"testing"
+
+	"S-AES/utils/saes"
+)
+
+func TestDeriveKnownAnswers(t *testing.T) {
+	cases := []struct {
+		name     string
+		password string
+		salt     string
+		params   Params
+		want     string
+	}{
+		// PBKDF2-HMAC-SHA256("password", "salt", 1) 的前 6 字节。
+		{"pbkdf2", "password", "salt", Params{Algorithm: PBKDF2, Iterations: 1}, "0x120FB6CFFCF8"},
+		// RFC 7914 第 12 节 scrypt("password", "NaCl", 1024, 8, 16) 的前 6 字节。
+		{"scrypt", "password", "NaCl", Params{Algorithm: Scrypt, N: 1024, R: 8, Parallelism: 16}, "0xFDBABE1C9D34"},
+	}
+	for _, tc := range cases {
+		got, err := DeriveKey([]byte(tc.password), []byte(tc.salt), 48, tc.params)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if got != tc.want {
+			t.Fatalf("%s: 得到 %s，期望 %s", tc.name, got, tc.want)
+		}
+		// 较短的密钥是同一输出的前缀。
+		short, err := DeriveKey([]byte(tc.password), []byte(tc.salt), 16, tc.params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if short != tc.want[:6] {
+			t.Fatalf("%s: 16 位密钥 %s 不是 %s 的前缀", tc.name, short, tc.want)
+		}
+	}
+}
+
+func TestArgon2idSaltSensitivity(t *testing.T) {
+	p := Params{Algorithm: Argon2id, Memory: 64, Parallelism: 1}
+	a, err := DeriveKey([]byte("correct horse"), []byte("salt-one"), 32, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := DeriveKey([]byte("correct horse"), []byte("salt-one"), 32, p)
+	c, _ := DeriveKey([]byte("correct horse"), []byte("salt-two"), 32, p)
+	if a != b || a == c || len(a) != 10 {
+		t.Fatalf("Argon2id 派生结果异常: %s %s %s", a, b, c)
+	}
+	if _, err := saes.ParseKey(a); err != nil {
+		t.Fatalf("派生密钥无法作为 S-AES 密钥: %v", err)
+	}
+}
+
+func TestParamsValidation(t *testing.T) {
+	bad := []Params{
+		{Algorithm: "md5"},
+		{Algorithm: Scrypt, N: 1000},
+		{Algorithm: Scrypt, N: 1 << 20, R: 1 << 20},
+		{Algorithm: Scrypt, N: 1 << 18, R: 64, Parallelism: 1},
+		{Algorithm: Argon2id, Memory: maxMemoryKiB + 1},
+		{Algorithm: PBKDF2, Iterations: -1},
+	}
+	for _, p := range bad {
+		if _, err := Derive([]byte("pw"), []byte("salt"), 16, p); err == nil {
+			t.Fatalf("参数 %+v 应被拒绝", p)
+		}
+	}
+	// 恰好 256 MiB 的 scrypt 参数可以通过校验。
+	if _, err := (Params{Algorithm: Scrypt, N: 1 << 20, R: 1, Parallelism: 2}).Resolve(); err != nil {
+		t.Fatalf("256 MiB 的 scrypt 参数应被接受: %v", err)
+	}
+	if _, err := Derive([]byte("pw"), []byte("salt"), 24, Params{}); err == nil {
+		t.Fatal("24 位密钥应被拒绝")
+	}
+	if _, err := Derive([]byte("pw"), nil, 16, Params{}); err == nil {
+		t.Fatal("空盐应被拒绝")
+	}
+}
+
+func TestCrackRecoversDerivedKey(t *testing.T) {
+	p := Params{Algorithm: PBKDF2, Iterations: 1000}
+	for _, bits := range []int{16, 32} {
+		key, err := Derive([]byte("Tr0ub4dor&3"), []byte("per-user salt"), bits, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := Crack(saes.Standard, key, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Recovered {
+			t.Fatalf("%d 位: 未恢复派生密钥 %s，候选 %v", bits, FormatKey(key), result.Candidates)
+		}
+	}
+	if _, err := Crack(saes.Standard, make([]byte, 6), 0); err == nil {
+		t.Fatal("48 位密钥应返回错误")
+	}
+}
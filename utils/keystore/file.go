@@ -0,0 +1,140 @@
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// 密钥库文件为 JSON，密钥列表经 AES-256-GCM 加密后存于 data 字段；
+// 加密密钥由口令经 scrypt 派生，盐在文件创建时生成并保持不变，每次保存使用新的 nonce。
+const (
+	fileFormat  = "saes-keystore"
+	fileVersion = 1
+
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+type fileEnvelope struct {
+	Format  string  `json:"format"`
+	Version int     `json:"version"`
+	KDF     fileKDF `json:"kdf"`
+	Salt    []byte  `json:"salt"`
+	Nonce   []byte  `json:"nonce"`
+	Data    []byte  `json:"data"`
+}
+
+type fileKDF struct {
+	N int `json:"n"`
+	R int `json:"r"`
+	P int `json:"p"`
+}
+
+// Open 打开 path 处的加密密钥库，文件不存在时创建空库（首次修改时写入）。
+// 口令错误或文件被篡改时返回错误。
+func Open(path string, passphrase []byte) (*Store, error) {
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("密钥库口令不能为空")
+	}
+
+	env := fileEnvelope{Format: fileFormat, Version: fileVersion, KDF: fileKDF{N: scryptN, R: scryptR, P: scryptP}}
+	raw, err := os.ReadFile(path)
+	exists := err == nil
+	switch {
+	case exists:
+		if err := json.Unmarshal(raw, &env); err != nil {
+			return nil, fmt.Errorf("密钥库文件格式错误: %w", err)
+		}
+		if env.Format != fileFormat || env.Version != fileVersion {
+			return nil, fmt.Errorf("不支持的密钥库文件: %s v%d", env.Format, env.Version)
+		}
+	case errors.Is(err, os.ErrNotExist):
+		env.Salt = make([]byte, 16)
+		if _, err := rand.Read(env.Salt); err != nil {
+			return nil, fmt.Errorf("生成盐失败: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("读取密钥库失败: %w", err)
+	}
+
+	key, err := scrypt.Key(passphrase, env.Salt, env.KDF.N, env.KDF.R, env.KDF.P, 32)
+	if err != nil {
+		return nil, fmt.Errorf("派生密钥库加密密钥失败: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	aad := []byte(fmt.Sprintf("%s v%d", fileFormat, fileVersion))
+
+	s := NewMemory()
+	if exists {
+		plain, err := aead.Open(nil, env.Nonce, env.Data, aad)
+		if err != nil {
+			return nil, fmt.Errorf("无法解密密钥库：口令错误或文件已损坏")
+		}
+		if err := json.Unmarshal(plain, &s.keys); err != nil {
+			return nil, fmt.Errorf("密钥库内容格式错误: %w", err)
+		}
+		for name, versions := range s.keys {
+			if len(versions) == 0 {
+				delete(s.keys, name)
+			}
+		}
+	}
+
+	s.persist = func(keys map[string][]*Key) error {
+		plain, err := json.Marshal(keys)
+		if err != nil {
+			return err
+		}
+		env.Nonce = make([]byte, aead.NonceSize())
+		if _, err := rand.Read(env.Nonce); err != nil {
+			return fmt.Errorf("生成 nonce 失败: %w", err)
+		}
+		env.Data = aead.Seal(nil, env.Nonce, plain, aad)
+		out, err := json.MarshalIndent(env, "", "  ")
+		if err != nil {
+			return err
+		}
+		return writeFileAtomic(path, out)
+	}
+	return s, nil
+}
+
+// writeFileAtomic 先写入同目录下的临时文件再重命名，避免中途失败留下残缺的密钥库。
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".keystore-*")
+	if err != nil {
+		return fmt.Errorf("保存密钥库失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("保存密钥库失败: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("保存密钥库失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("保存密钥库失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("保存密钥库失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+// Package keystore 在服务端保存按名称与版本管理的 S-AES 密钥，客户端只需引用 key_id，
+// 无需在请求中传递密钥本身。密钥库可仅驻留内存，也可加密保存到本地文件（见 file.go）。
+//
+// 轮换生成新版本后，旧版本仍保留用于解密；密文以 "saes:vN:" 前缀记录加密所用的版本。
+package keystore
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"S-AES/utils/kdf"
+)
+
+// PrefixTag 为密文版本前缀的固定开头，完整前缀形如 "saes:v2:"。
+const PrefixTag = "saes:v"
+
+var (
+	// ErrNotFound 表示密钥名称或版本不存在。
+	ErrNotFound = errors.New("密钥不存在")
+	// ErrExists 表示创建的密钥名称已被占用。
+	ErrExists = errors.New("密钥名称已存在")
+
+	namePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+)
+
+// Key 为某个密钥名称下的一个版本。
+type Key struct {
+	Name     string    `json:"name"`
+	Version  int       `json:"version"`
+	Bits     int       `json:"bits"`
+	Material []byte    `json:"material"`
+	Created  time.Time `json:"created"`
+}
+
+// Hex 返回可直接用于各加解密接口的 0x 十六进制密钥。
+func (k *Key) Hex() string {
+	return kdf.FormatKey(k.Material)
+}
+
+// Ref 返回 "name:vN" 形式的引用，用于容器与 ASCII 封装的 Key-ID。
+func (k *Key) Ref() string {
+	return fmt.Sprintf("%s:v%d", k.Name, k.Version)
+}
+
+// Info 为不含密钥材料的密钥概要。
+type Info struct {
+	Name     string        `json:"name"`
+	Current  int           `json:"current"`
+	Versions []VersionInfo `json:"versions"`
+}
+
+// VersionInfo 为单个版本的概要。
+type VersionInfo struct {
+	Version int       `json:"version"`
+	Bits    int       `json:"bits"`
+	Created time.Time `json:"created"`
+}
+
+// Store 为并发安全的密钥库。persist 非 nil 时每次修改后调用它保存全部密钥。
+type Store struct {
+	mu      sync.RWMutex
+	keys    map[string][]*Key
+	persist func(map[string][]*Key) error
+}
+
+// NewMemory 返回仅驻留内存的空密钥库。
+func NewMemory() *Store {
+	return &Store{keys: make(map[string][]*Key)}
+}
+
+// Generate 用 crypto/rand 生成 bits 位（16、32 或 48）密钥材料。
+func Generate(bits int) ([]byte, error) {
+	if bits != 16 && bits != 32 && bits != 48 {
+		return nil, fmt.Errorf("密钥长度必须是 16、32 或 48 位")
+	}
+	material := make([]byte, bits/8)
+	if _, err := rand.Read(material); err != nil {
+		return nil, fmt.Errorf("生成密钥失败: %w", err)
+	}
+	return material, nil
+}
+
+// Create 以版本 1 新建名为 name 的密钥。
+func (s *Store) Create(name string, bits int) (*Key, error) {
+	if !namePattern.MatchString(name) {
+		return nil, fmt.Errorf("密钥名称只能包含字母、数字、'_'、'.'、'-'，长度 1 到 64")
+	}
+	material, err := Generate(bits)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.keys[name]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrExists, name)
+	}
+	key := &Key{Name: name, Version: 1, Bits: bits, Material: material, Created: time.Now().UTC()}
+	s.keys[name] = []*Key{key}
+	if err := s.save(); err != nil {
+		delete(s.keys, name)
+		return nil, err
+	}
+	return key, nil
+}
+
+// Rotate 为 name 生成新版本并设为当前版本；bits 为 0 时沿用当前版本的长度。
+func (s *Store) Rotate(name string, bits int) (*Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	versions, ok := s.keys[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	current := versions[len(versions)-1]
+	if bits == 0 {
+		bits = current.Bits
+	}
+	material, err := Generate(bits)
+	if err != nil {
+		return nil, err
+	}
+	key := &Key{Name: name, Version: current.Version + 1, Bits: bits, Material: material, Created: time.Now().UTC()}
+	s.keys[name] = append(versions, key)
+	if err := s.save(); err != nil {
+		s.keys[name] = versions
+		return nil, err
+	}
+	return key, nil
+}
+
+// Get 返回 name 的指定版本，version 为 0 时返回当前版本。
+func (s *Store) Get(name string, version int) (*Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	versions, ok := s.keys[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if version == 0 {
+		return versions[len(versions)-1], nil
+	}
+	for _, k := range versions {
+		if k.Version == version {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s:v%d", ErrNotFound, name, version)
+}
+
+// Info 返回 name 的概要。
+func (s *Store) Info(name string) (*Info, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	versions, ok := s.keys[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return info(name, versions), nil
+}
+
+// List 按名称顺序返回全部密钥的概要。
+func (s *Store) List() []*Info {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]*Info, 0, len(s.keys))
+	for name, versions := range s.keys {
+		out = append(out, info(name, versions))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Delete 删除 name 的全部版本，之后用它加密的数据将无法解密。
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	versions, ok := s.keys[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	delete(s.keys, name)
+	if err := s.save(); err != nil {
+		s.keys[name] = versions
+		return err
+	}
+	return nil
+}
+
+func (s *Store) save() error {
+	if s.persist == nil {
+		return nil
+	}
+	return s.persist(s.keys)
+}
+
+func info(name string, versions []*Key) *Info {
+	out := &Info{Name: name, Current: versions[len(versions)-1].Version}
+	for _, k := range versions {
+		out.Versions = append(out.Versions, VersionInfo{Version: k.Version, Bits: k.Bits, Created: k.Created})
+	}
+	return out
+}
+
+// ParseRef 解析 "name" 或 "name:vN" 形式的密钥引用，未指定版本时 version 为 0。
+func ParseRef(ref string) (name string, version int, err error) {
+	ref = strings.TrimSpace(ref)
+	name = ref
+	if i := strings.LastIndex(ref, ":v"); i >= 0 {
+		v, err := strconv.Atoi(ref[i+2:])
+		if err != nil || v < 1 {
+			return "", 0, fmt.Errorf("无法解析密钥引用: %s", ref)
+		}
+		name, version = ref[:i], v
+	}
+	if !namePattern.MatchString(name) {
+		return "", 0, fmt.Errorf("无法解析密钥引用: %s", ref)
+	}
+	return name, version, nil
+}
+
+// Prefix 返回版本 version 的密文前缀，如 "saes:v2:"。
+func Prefix(version int) string {
+	return PrefixTag + strconv.Itoa(version) + ":"
+}
+
+// SplitPrefix 去除 s 开头的版本前缀，返回其余部分与版本号；没有前缀时原样返回 s，version 为 0。
+// 只忽略前缀之前的空白，其余内容（可能是原始字节）保持不变。
+func SplitPrefix(s string) (rest string, version int) {
+	trimmed := strings.TrimLeft(s, " \t\r\n")
+	if !strings.HasPrefix(trimmed, PrefixTag) {
+		return s, 0
+	}
+	body := trimmed[len(PrefixTag):]
+	end := strings.IndexByte(body, ':')
+	if end < 0 {
+		return s, 0
+	}
+	v, err := strconv.Atoi(body[:end])
+	if err != nil || v < 1 {
+		return s, 0
+	}
+	return body[end+1:], v
+}
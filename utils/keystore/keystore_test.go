@@ -0,0 +1,100 @@
+package keystore
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"S-AES/utils/saes"
+)
+
+func TestCreateRotateGet(t *testing.T) {
+	s := NewMemory()
+	k1, err := s.Create("payments", 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k1.Version != 1 || len(k1.Material) != 4 {
+		t.Fatalf("版本 1 异常: %+v", k1)
+	}
+	if _, err := saes.ParseKey(k1.Hex()); err != nil {
+		t.Fatalf("生成的密钥无法解析: %v", err)
+	}
+	if _, err := s.Create("payments", 16); !errors.Is(err, ErrExists) {
+		t.Fatalf("重复创建应返回 ErrExists，得到 %v", err)
+	}
+
+	k2, err := s.Rotate("payments", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k2.Version != 2 || k2.Bits != 32 {
+		t.Fatalf("轮换结果异常: %+v", k2)
+	}
+	cur, _ := s.Get("payments", 0)
+	old, _ := s.Get("payments", 1)
+	if cur != k2 || old != k1 {
+		t.Fatal("Get 返回的版本不正确")
+	}
+	if _, err := s.Get("payments", 3); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("不存在的版本应返回 ErrNotFound，得到 %v", err)
+	}
+	if _, err := s.Create("bad name", 16); err == nil {
+		t.Fatal("非法名称应被拒绝")
+	}
+}
+
+func TestFilePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	s, err := Open(path, []byte("correct passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := s.Create("backup", 48)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := s.Rotate("backup", 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := Open(path, []byte("correct passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []*Key{created, rotated} {
+		got, err := reopened.Get("backup", want.Version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Hex() != want.Hex() || got.Bits != want.Bits {
+			t.Fatalf("v%d 重新打开后不一致: %s != %s", want.Version, got.Hex(), want.Hex())
+		}
+	}
+
+	if _, err := Open(path, []byte("wrong passphrase")); err == nil {
+		t.Fatal("错误口令应无法打开密钥库")
+	}
+}
+
+func TestPrefixAndRef(t *testing.T) {
+	rest, v := SplitPrefix(Prefix(12) + "Qph0n+Gy9SmV/Q==")
+	if rest != "Qph0n+Gy9SmV/Q==" || v != 12 {
+		t.Fatalf("SplitPrefix = %q, %d", rest, v)
+	}
+	if rest, v := SplitPrefix("0110111101101011"); rest != "0110111101101011" || v != 0 {
+		t.Fatal("无前缀的输入不应被修改")
+	}
+
+	name, version, err := ParseRef("team.api:v3")
+	if err != nil || name != "team.api" || version != 3 {
+		t.Fatalf("ParseRef = %q, %d, %v", name, version, err)
+	}
+	if name, version, err := ParseRef("team.api"); err != nil || name != "team.api" || version != 0 {
+		t.Fatalf("ParseRef 无版本 = %q, %d, %v", name, version, err)
+	}
+	if _, _, err := ParseRef("x:v0"); err == nil {
+		t.Fatal("版本 0 应被拒绝")
+	}
+}
@@ -0,0 +1,119 @@
+package prp
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"S-AES/utils/saes"
+)
+
+func mustBlock(t *testing.T, key string) *saes.KeyedBlock {
+	t.Helper()
+	kb, err := saes.NewKeyedBlock(saes.Standard, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return kb
+}
+
+func TestPermutationIsBijection(t *testing.T) {
+	kb := mustBlock(t, "0x2D55")
+	for _, size := range []int{1, 2, 10, 1000, MaxSize} {
+		p, err := New(kb, size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen := make([]bool, size)
+		for i := 0; i < size; i++ {
+			v, err := p.Permute(i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if seen[v] {
+				t.Fatalf("size=%d: π(%d)=%d 重复", size, i, v)
+			}
+			seen[v] = true
+			if back, _ := p.Invert(v); back != i {
+				t.Fatalf("size=%d: π⁻¹(%d)=%d，期望 %d", size, v, back, i)
+			}
+		}
+	}
+}
+
+func TestFullDomainMatchesCipher(t *testing.T) {
+	// 定义域为全部 2^16 个值时不需要游走，置换就是一次加密。
+	p, _ := New(mustBlock(t, "0xA73B"), MaxSize)
+	if v, _ := p.Permute(0x6F6B); v != 0x0738 {
+		t.Fatalf("π(0x6F6B)=0x%04X，期望 0x0738", v)
+	}
+	if _, err := p.Permute(MaxSize); err == nil {
+		t.Fatal("定义域外的下标应被拒绝")
+	}
+	if _, err := New(p.block, MaxSize+1); err == nil {
+		t.Fatal("超过 2^16 的定义域应被拒绝")
+	}
+}
+
+func TestShuffleDeterministic(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e", "f", "g"}
+	first, err := Shuffle(mustBlock(t, "0x1234"), items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := Shuffle(mustBlock(t, "0x1234"), items)
+	if strings.Join(first, "") != strings.Join(second, "") {
+		t.Fatal("同一密钥的洗牌结果不一致")
+	}
+	other, _ := Shuffle(mustBlock(t, "0x4321"), items)
+	if strings.Join(first, "") == strings.Join(other, "") {
+		t.Fatal("不同密钥得到了相同顺序")
+	}
+	back, _ := Unshuffle(mustBlock(t, "0x1234"), first)
+	if strings.Join(back, "") != strings.Join(items, "") {
+		t.Fatalf("恢复顺序失败: %v", back)
+	}
+}
+
+func TestPageAndCSV(t *testing.T) {
+	p, _ := New(mustBlock(t, "0x1234"), 100)
+	page, err := p.Page(95, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 5 {
+		t.Fatalf("末页应截断为 5 项，得到 %d", len(page))
+	}
+	var buf bytes.Buffer
+	if err := p.WriteCSV(&buf, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 101 || lines[0] != "index,value" {
+		t.Fatalf("CSV 应有表头加 100 行，得到 %d 行", len(lines))
+	}
+	v, _ := p.Permute(96)
+	if want := "96," + strconv.Itoa(v); lines[97] != want {
+		t.Fatalf("第 96 行为 %q，期望 %q", lines[97], want)
+	}
+}
+
+func TestHugeLimit(t *testing.T) {
+	p, _ := New(mustBlock(t, "0x1234"), 100)
+	page, err := p.Page(1, math.MaxInt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 99 {
+		t.Fatalf("limit 超出表尾时应截断为 99 项，得到 %d", len(page))
+	}
+	var buf bytes.Buffer
+	if err := p.WriteJSONL(&buf, 1, math.MaxInt); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(buf.String(), "\n"); n != 99 {
+		t.Fatalf("JSON lines 应有 99 行，得到 %d", n)
+	}
+}
@@ -0,0 +1,315 @@
+// Package randtest 实现 NIST SP 800-22 风格的随机性统计检验：单比特频数、游程、扑克、
+// 序列、自相关与近似熵。每项检验给出统计量与 P 值，P 值不小于显著性水平即通过。
+// 输入按字节从高位到低位展开为比特序列。
+package randtest
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+const (
+	// DefaultAlpha 为默认显著性水平。
+	DefaultAlpha = 0.01
+	// MinBits 为运行检验所需的最少比特数。
+	MinBits = 100
+)
+
+// Options 为各检验的参数，零值按序列长度自动选取。
+type Options struct {
+	Alpha float64
+	// PokerM 为扑克检验的分组位数，默认 4。
+	PokerM int
+	// SerialM 为序列检验的模式长度，默认 ⌊log2 n⌋ - 3（不超过 16）。
+	SerialM int
+	// EntropyM 为近似熵检验的模式长度，默认 ⌊log2 n⌋ - 6（2 到 10 之间）。
+	EntropyM int
+	// Lag 为自相关检验的位移，默认 16（一个分组）。
+	Lag int
+}
+
+// Result 为单项检验的结果；序列检验有两个 P 值，PValue 取其中较小者。
+type Result struct {
+	Name      string    `json:"name"`
+	Statistic float64   `json:"statistic"`
+	PValue    float64   `json:"p_value"`
+	PValues   []float64 `json:"p_values,omitempty"`
+	Params    string    `json:"params,omitempty"`
+	Passed    bool      `json:"passed"`
+}
+
+// Report 为整组检验的结果。
+type Report struct {
+	Bits    int      `json:"bits"`
+	Alpha   float64  `json:"alpha"`
+	Results []Result `json:"results"`
+	Passed  int      `json:"passed"`
+	Failed  int      `json:"failed"`
+}
+
+// Run 对 data 依次运行全部检验。
+func Run(data []byte, opts Options) (*Report, error) {
+	n := len(data) * 8
+	if n < MinBits {
+		return nil, fmt.Errorf("至少需要 %d 比特（%d 字节）", MinBits, (MinBits+7)/8)
+	}
+	alpha := opts.Alpha
+	if alpha == 0 {
+		alpha = DefaultAlpha
+	}
+	if alpha <= 0 || alpha >= 1 {
+		return nil, fmt.Errorf("显著性水平必须在 0 到 1 之间")
+	}
+	logN := bits.Len(uint(n)) - 1
+
+	pokerM := opts.PokerM
+	if pokerM == 0 {
+		pokerM = 4
+	}
+	serialM := opts.SerialM
+	if serialM == 0 {
+		serialM = min(max(logN-3, 2), 16)
+	}
+	entropyM := opts.EntropyM
+	if entropyM == 0 {
+		entropyM = min(max(logN-6, 2), 10)
+	}
+	lag := opts.Lag
+	if lag == 0 {
+		lag = 16
+	}
+	switch {
+	case pokerM < 1 || pokerM > 16 || n/pokerM < 1<<pokerM:
+		return nil, fmt.Errorf("扑克检验的分组位数 %d 对 %d 比特不适用", pokerM, n)
+	case serialM < 2 || serialM > 16 || serialM >= logN-1:
+		return nil, fmt.Errorf("序列检验的模式长度 %d 对 %d 比特不适用", serialM, n)
+	case entropyM < 1 || entropyM > 16 || entropyM >= logN-1:
+		return nil, fmt.Errorf("近似熵检验的模式长度 %d 对 %d 比特不适用", entropyM, n)
+	case lag < 1 || lag > n/2:
+		return nil, fmt.Errorf("自相关位移必须在 1 到 %d 之间", n/2)
+	}
+
+	s := bitSeq(data)
+	report := &Report{Bits: n, Alpha: alpha}
+	for _, r := range []Result{
+		Monobit(s),
+		Runs(s),
+		Poker(s, pokerM),
+		Serial(s, serialM),
+		Autocorrelation(s, lag),
+		ApproximateEntropy(s, entropyM),
+	} {
+		r.Passed = r.PValue >= alpha
+		if r.Passed {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+		report.Results = append(report.Results, r)
+	}
+	return report, nil
+}
+
+// bitSeq 把字节展开为 0/1 比特序列（高位在前）。
+func bitSeq(data []byte) []byte {
+	s := make([]byte, 0, len(data)*8)
+	for _, b := range data {
+		for i := 7; i >= 0; i-- {
+			s = append(s, b>>i&1)
+		}
+	}
+	return s
+}
+
+// Monobit 为频数检验（SP 800-22 2.1）：S_obs = |Σ(2ε-1)|/√n。
+func Monobit(s []byte) Result {
+	n := float64(len(s))
+	sum := 0
+	for _, b := range s {
+		sum += 2*int(b) - 1
+	}
+	obs := math.Abs(float64(sum)) / math.Sqrt(n)
+	return Result{Name: "monobit", Statistic: obs, PValue: math.Erfc(obs / math.Sqrt2)}
+}
+
+// Runs 为游程检验（SP 800-22 2.3），频数检验的前提不满足时 P 值记为 0。
+func Runs(s []byte) Result {
+	n := float64(len(s))
+	ones := 0
+	for _, b := range s {
+		ones += int(b)
+	}
+	pi := float64(ones) / n
+	if math.Abs(pi-0.5) >= 2/math.Sqrt(n) {
+		return Result{Name: "runs", Statistic: pi, PValue: 0, Params: "1 的比例偏离过大，未进行游程检验"}
+	}
+	v := 1
+	for i := 1; i < len(s); i++ {
+		if s[i] != s[i-1] {
+			v++
+		}
+	}
+	num := math.Abs(float64(v) - 2*n*pi*(1-pi))
+	den := 2 * math.Sqrt(2*n) * pi * (1 - pi)
+	return Result{Name: "runs", Statistic: float64(v), PValue: math.Erfc(num / den)}
+}
+
+// Poker 为扑克检验（FIPS 140-1）：把序列切成 m 位一组，统计 2^m 种取值的 χ²。
+func Poker(s []byte, m int) Result {
+	k := len(s) / m
+	counts := make([]float64, 1<<m)
+	for i := 0; i < k; i++ {
+		counts[pattern(s, i*m, m)]++
+	}
+	sum := 0.0
+	for _, f := range counts {
+		sum += f * f
+	}
+	x := float64(int(1)<<m)/float64(k)*sum - float64(k)
+	return Result{
+		Name:      "poker",
+		Statistic: x,
+		PValue:    igamc(float64(int(1)<<m-1)/2, x/2),
+		Params:    fmt.Sprintf("m=%d", m),
+	}
+}
+
+// Serial 为序列检验（SP 800-22 2.11），统计循环扩展后所有 m 位重叠模式的频数。
+func Serial(s []byte, m int) Result {
+	p0, p1, p2 := psiSquared(s, m), psiSquared(s, m-1), psiSquared(s, m-2)
+	d1 := p0 - p1
+	d2 := p0 - 2*p1 + p2
+	pv1 := igamc(math.Pow(2, float64(m-2)), d1/2)
+	pv2 := igamc(math.Pow(2, float64(m-3)), d2/2)
+	return Result{
+		Name:      "serial",
+		Statistic: d1,
+		PValue:    math.Min(pv1, pv2),
+		PValues:   []float64{pv1, pv2},
+		Params:    fmt.Sprintf("m=%d", m),
+	}
+}
+
+// Autocorrelation 检验序列与其位移 d 后的版本的异或（HAC 5.4.4）：A(d) 近似服从正态分布。
+func Autocorrelation(s []byte, d int) Result {
+	n := len(s) - d
+	a := 0
+	for i := 0; i < n; i++ {
+		a += int(s[i] ^ s[i+d])
+	}
+	x := 2 * (float64(a) - float64(n)/2) / math.Sqrt(float64(n))
+	return Result{
+		Name:      "autocorrelation",
+		Statistic: x,
+		PValue:    math.Erfc(math.Abs(x) / math.Sqrt2),
+		Params:    fmt.Sprintf("d=%d", d),
+	}
+}
+
+// ApproximateEntropy 为近似熵检验（SP 800-22 2.12）：比较 m 与 m+1 位重叠模式的频率熵。
+func ApproximateEntropy(s []byte, m int) Result {
+	n := float64(len(s))
+	apEn := phi(s, m) - phi(s, m+1)
+	chi := 2 * n * (math.Ln2 - apEn)
+	return Result{
+		Name:      "approximate_entropy",
+		Statistic: chi,
+		PValue:    igamc(math.Pow(2, float64(m-1)), chi/2),
+		Params:    fmt.Sprintf("m=%d", m),
+	}
+}
+
+// countPatterns 统计循环扩展后的 m 位重叠模式。
+func countPatterns(s []byte, m int) []float64 {
+	counts := make([]float64, 1<<m)
+	if m == 0 {
+		counts[0] = float64(len(s))
+		return counts
+	}
+	mask := 1<<m - 1
+	v := 0
+	for i := 0; i < m-1; i++ {
+		v = v<<1 | int(s[i])
+	}
+	for i := 0; i < len(s); i++ {
+		v = (v<<1 | int(s[(i+m-1)%len(s)])) & mask
+		counts[v]++
+	}
+	return counts
+}
+
+func psiSquared(s []byte, m int) float64 {
+	if m <= 0 {
+		return 0
+	}
+	n := float64(len(s))
+	sum := 0.0
+	for _, c := range countPatterns(s, m) {
+		sum += c * c
+	}
+	return math.Pow(2, float64(m))/n*sum - n
+}
+
+func phi(s []byte, m int) float64 {
+	n := float64(len(s))
+	sum := 0.0
+	for _, c := range countPatterns(s, m) {
+		if c > 0 {
+			p := c / n
+			sum += p * math.Log(p)
+		}
+	}
+	return sum
+}
+
+func pattern(s []byte, off, m int) int {
+	v := 0
+	for i := 0; i < m; i++ {
+		v = v<<1 | int(s[off+i])
+	}
+	return v
+}
+
+// igamc 为正则化上不完全伽马函数 Q(a, x)，x < a+1 时用级数，否则用连分式（Numerical Recipes 6.2）。
+func igamc(a, x float64) float64 {
+	if x <= 0 || a <= 0 {
+		return 1
+	}
+	lg, _ := math.Lgamma(a)
+	if x < a+1 {
+		sum, term := 1/a, 1/a
+		for n := 1; n < 1000; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*1e-15 {
+				break
+			}
+		}
+		return 1 - sum*math.Exp(-x+a*math.Log(x)-lg)
+	}
+	const tiny = 1e-300
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1; i < 1000; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+	return math.Exp(-x+a*math.Log(x)-lg) * h
+}
@@ -0,0 +1,61 @@
+package randtest
+
+import (
+	"math"
+	"testing"
+)
+
+func bitsOf(s string) []byte {
+	out := make([]byte, len(s))
+	for i := range s {
+		out[i] = s[i] - '0'
+	}
+	return out
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+// 向量取自 SP 800-22 各检验的示例。
+func TestNISTExamples(t *testing.T) {
+	if r := Monobit(bitsOf("1011010101")); !near(r.PValue, 0.527089) {
+		t.Fatalf("monobit P = %f", r.PValue)
+	}
+	if r := Runs(bitsOf("1001101011")); !near(r.PValue, 0.147232) {
+		t.Fatalf("runs P = %f", r.PValue)
+	}
+	if r := Serial(bitsOf("0011011101"), 3); !near(r.PValues[0], 0.808792) || !near(r.PValues[1], 0.670320) {
+		t.Fatalf("serial P = %v", r.PValues)
+	}
+	if r := ApproximateEntropy(bitsOf("0100110101"), 3); !near(r.PValue, 0.261961) {
+		t.Fatalf("approximate entropy P = %f", r.PValue)
+	}
+}
+
+func TestRunDetectsStructure(t *testing.T) {
+	// 周期为 16 比特、0 与 1 各半的序列：频数检验通过，但扑克、自相关等应失败。
+	data := make([]byte, 4096)
+	for i := range data {
+		data[i] = []byte{0x5A, 0xC3}[i%2]
+	}
+	report, err := Run(data, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	failed := map[string]bool{}
+	for _, r := range report.Results {
+		failed[r.Name] = !r.Passed
+	}
+	if failed["monobit"] {
+		t.Fatal("0/1 各半的序列应通过频数检验")
+	}
+	for _, name := range []string{"poker", "serial", "autocorrelation", "approximate_entropy"} {
+		if !failed[name] {
+			t.Fatalf("周期序列应无法通过 %s 检验", name)
+		}
+	}
+	if _, err := Run(make([]byte, 4), Options{}); err == nil {
+		t.Fatal("过短的输入应被拒绝")
+	}
+}
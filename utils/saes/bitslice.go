@@ -0,0 +1,319 @@
+package saes
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// 位切片实现：64 个互相独立的 (分组, 密钥) 通道并行处理，第 i 个 uint64 保存所有通道状态的第 i 位。
+// S 盒由 ANF 布尔电路求值，列混淆为 GF(2) 上的线性变换，全程没有依赖数据的分支与查表。
+
+// BatchLanes 为一次位切片运算处理的通道数。
+const BatchLanes = 64
+
+type slice16 = [16]uint64
+
+// BatchCipher 在 BlockCipher 之上支持批量加解密：out[i] = E(blocks[i], keys[i])。
+// blocks 长度为 1 时对所有密钥复用同一个分组。
+type BatchCipher interface {
+	BlockCipher
+	EncryptBatch(blocks, keys, out []uint16)
+	DecryptBatch(blocks, keys, out []uint16)
+}
+
+// BitslicedCipher 是与 Cipher 逐位等价的位切片实现。
+type BitslicedCipher struct {
+	rounds   int
+	finalMix bool
+	rcon     []byte
+	// sBoxTerms[o] 为 S 盒第 o 个输出位 ANF 中系数为 1 的单项式（变量掩码）。
+	sBoxTerms    [4][]uint8
+	invSBoxTerms [4][]uint8
+	// mix/invMix[k][o] 为与矩阵第 k 个系数相乘时，第 o 个输出位依赖的输入位掩码。
+	mix    [4][4]uint8
+	invMix [4][4]uint8
+}
+
+// Bitsliced 为标准 S-AES 的位切片实现。
+var Bitsliced = NewBitslicedCipher(standardGeneric)
+
+// NewBitslicedCipher 由 Cipher 的参数推导 S 盒电路与线性层。
+func NewBitslicedCipher(c *Cipher) *BitslicedCipher {
+	b := &BitslicedCipher{
+		rounds:   c.params.Rounds,
+		finalMix: c.params.FinalMixColumns,
+		rcon:     c.RoundConstants(),
+	}
+	for o := 0; o < 4; o++ {
+		b.sBoxTerms[o] = anfTerms(CoordinateANF(c.sBox, o))
+		b.invSBoxTerms[o] = anfTerms(CoordinateANF(c.invSBox, o))
+	}
+	for k := 0; k < 4; k++ {
+		b.mix[k] = mulMatrix(c, c.mix[k])
+		b.invMix[k] = mulMatrix(c, c.invMix[k])
+	}
+	return b
+}
+
+// EncryptBatch 将 out[i] 设为 blocks[i]（或 blocks[0]）在 keys[i] 下的密文。
+func (b *BitslicedCipher) EncryptBatch(blocks, keys, out []uint16) {
+	b.batch(blocks, keys, out, b.encryptSlice)
+}
+
+// DecryptBatch 将 out[i] 设为 blocks[i]（或 blocks[0]）在 keys[i] 下的明文。
+func (b *BitslicedCipher) DecryptBatch(blocks, keys, out []uint16) {
+	b.batch(blocks, keys, out, b.decryptSlice)
+}
+
+// EncryptBlock 实现 BlockCipher（仅使用一个通道）。
+func (b *BitslicedCipher) EncryptBlock(block, key uint16) uint16 {
+	var out [1]uint16
+	b.EncryptBatch([]uint16{block}, []uint16{key}, out[:])
+	return out[0]
+}
+
+// DecryptBlock 实现 BlockCipher（仅使用一个通道）。
+func (b *BitslicedCipher) DecryptBlock(block, key uint16) uint16 {
+	var out [1]uint16
+	b.DecryptBatch([]uint16{block}, []uint16{key}, out[:])
+	return out[0]
+}
+
+// EncryptBatch 使用标准 S-AES 的位切片实现批量加密。
+func EncryptBatch(blocks, keys, out []uint16) {
+	Bitsliced.EncryptBatch(blocks, keys, out)
+}
+
+// DecryptBatch 使用标准 S-AES 的位切片实现批量解密。
+func DecryptBatch(blocks, keys, out []uint16) {
+	Bitsliced.DecryptBatch(blocks, keys, out)
+}
+
+// EncryptBatchWith 在 bc 支持 BatchCipher 时批量加密，否则逐个分组加密。
+func EncryptBatchWith(bc BlockCipher, blocks, keys, out []uint16) {
+	if bb, ok := bc.(BatchCipher); ok {
+		bb.EncryptBatch(blocks, keys, out)
+		return
+	}
+	checkBatch(blocks, keys, out)
+	for i, k := range keys {
+		out[i] = bc.EncryptBlock(batchBlock(blocks, i), k)
+	}
+}
+
+// DecryptBatchWith 在 bc 支持 BatchCipher 时批量解密，否则逐个分组解密。
+func DecryptBatchWith(bc BlockCipher, blocks, keys, out []uint16) {
+	if bb, ok := bc.(BatchCipher); ok {
+		bb.DecryptBatch(blocks, keys, out)
+		return
+	}
+	checkBatch(blocks, keys, out)
+	for i, k := range keys {
+		out[i] = bc.DecryptBlock(batchBlock(blocks, i), k)
+	}
+}
+
+func (b *BitslicedCipher) batch(blocks, keys, out []uint16, fn func(state, key *slice16)) {
+	checkBatch(blocks, keys, out)
+	for base := 0; base < len(keys); base += BatchLanes {
+		n := min(BatchLanes, len(keys)-base)
+		var rows [BatchLanes]uint64
+		var state slice16
+		if len(blocks) == 1 {
+			// 所有通道共用同一分组时，每个位平面要么全 0 要么全 1，无需转置。
+			for i := range state {
+				state[i] = -uint64((blocks[0] >> i) & 1)
+			}
+		} else {
+			for lane := 0; lane < n; lane++ {
+				rows[63-lane] = uint64(blocks[base+lane])
+			}
+			state = toSlices(&rows)
+			clear(rows[:])
+		}
+		for lane := 0; lane < n; lane++ {
+			rows[63-lane] = uint64(keys[base+lane])
+		}
+		key := toSlices(&rows)
+
+		fn(&state, &key)
+
+		clear(rows[:])
+		for i := range state {
+			rows[63-i] = state[i]
+		}
+		transpose64(&rows)
+		for lane := 0; lane < n; lane++ {
+			out[base+lane] = uint16(rows[63-lane])
+		}
+	}
+}
+
+func (b *BitslicedCipher) encryptSlice(state, key *slice16) {
+	var roundKeys [MaxRounds + 1]slice16
+	b.expandKey(key, &roundKeys)
+	xorSlice(state, &roundKeys[0])
+	for r := 1; r <= b.rounds; r++ {
+		b.subNib(state, &b.sBoxTerms)
+		shiftRowsSlice(state)
+		if r < b.rounds || b.finalMix {
+			mixColumnsSlice(state, &b.mix)
+		}
+		xorSlice(state, &roundKeys[r])
+	}
+}
+
+func (b *BitslicedCipher) decryptSlice(state, key *slice16) {
+	var roundKeys [MaxRounds + 1]slice16
+	b.expandKey(key, &roundKeys)
+	for r := b.rounds; r >= 1; r-- {
+		xorSlice(state, &roundKeys[r])
+		if r < b.rounds || b.finalMix {
+			mixColumnsSlice(state, &b.invMix)
+		}
+		shiftRowsSlice(state)
+		b.subNib(state, &b.invSBoxTerms)
+	}
+	xorSlice(state, &roundKeys[0])
+}
+
+// expandKey 以位切片形式生成全部轮密钥，低 8 位为奇数字 w[2i+1]，高 8 位为偶数字 w[2i]。
+func (b *BitslicedCipher) expandKey(key *slice16, roundKeys *[MaxRounds + 1]slice16) {
+	roundKeys[0] = *key
+	rk := *key
+	for i := 1; i <= b.rounds; i++ {
+		// g：RotNib 交换奇数字的两个半字节，再逐半字节代换并异或轮常数。
+		var g [8]uint64
+		lo := b.sub(&b.sBoxTerms, [4]uint64{rk[4], rk[5], rk[6], rk[7]})
+		hi := b.sub(&b.sBoxTerms, [4]uint64{rk[0], rk[1], rk[2], rk[3]})
+		copy(g[0:4], lo[:])
+		copy(g[4:8], hi[:])
+		for bit := 0; bit < 8; bit++ {
+			g[bit] ^= -uint64((b.rcon[i-1] >> bit) & 1)
+			rk[8+bit] ^= g[bit]
+			rk[bit] ^= rk[8+bit]
+		}
+		roundKeys[i] = rk
+	}
+}
+
+func (b *BitslicedCipher) subNib(state *slice16, terms *[4][]uint8) {
+	for n := 0; n < 16; n += 4 {
+		y := b.sub(terms, [4]uint64{state[n], state[n+1], state[n+2], state[n+3]})
+		copy(state[n:n+4], y[:])
+	}
+}
+
+// sub 对 4 个位平面求值 S 盒的 ANF 电路。
+func (b *BitslicedCipher) sub(terms *[4][]uint8, x [4]uint64) [4]uint64 {
+	var mono [16]uint64
+	mono[0] = ^uint64(0)
+	for m := 1; m < 16; m++ {
+		mono[m] = mono[m&(m-1)] & x[bits.TrailingZeros(uint(m))]
+	}
+	var y [4]uint64
+	for o := range y {
+		for _, m := range terms[o] {
+			y[o] ^= mono[m]
+		}
+	}
+	return y
+}
+
+// shiftRowsSlice 交换半字节 n1（位 8..11）与 n3（位 0..3），自身即为逆运算。
+func shiftRowsSlice(state *slice16) {
+	for i := 0; i < 4; i++ {
+		state[8+i], state[i] = state[i], state[8+i]
+	}
+}
+
+func mixColumnsSlice(state *slice16, m *[4][4]uint8) {
+	// 第 0 列为 (n0, n1) = 位 (12..15, 8..11)，第 1 列为 (n2, n3) = 位 (4..7, 0..3)。
+	for _, col := range [2][2]int{{12, 8}, {4, 0}} {
+		a := [4]uint64{state[col[0]], state[col[0]+1], state[col[0]+2], state[col[0]+3]}
+		d := [4]uint64{state[col[1]], state[col[1]+1], state[col[1]+2], state[col[1]+3]}
+		for o := 0; o < 4; o++ {
+			state[col[0]+o] = applyMask(m[0][o], &a) ^ applyMask(m[1][o], &d)
+			state[col[1]+o] = applyMask(m[2][o], &a) ^ applyMask(m[3][o], &d)
+		}
+	}
+}
+
+func applyMask(mask uint8, x *[4]uint64) uint64 {
+	var out uint64
+	for i := 0; i < 4; i++ {
+		out ^= x[i] & -uint64((mask>>i)&1)
+	}
+	return out
+}
+
+func xorSlice(state, key *slice16) {
+	for i := range state {
+		state[i] ^= key[i]
+	}
+}
+
+// toSlices 转置 64 个通道（rows[63-lane] 为该通道的 16 位值），返回位平面。
+func toSlices(rows *[BatchLanes]uint64) slice16 {
+	transpose64(rows)
+	var s slice16
+	for i := range s {
+		s[i] = rows[63-i]
+	}
+	return s
+}
+
+// transpose64 原地转置 64x64 位矩阵：输入 a[r] 的第 c 位移动到 a[63-c] 的第 63-r 位，自身即为逆运算。
+func transpose64(a *[BatchLanes]uint64) {
+	j := 32
+	m := uint64(0x00000000FFFFFFFF)
+	for j != 0 {
+		for k := 0; k < 64; k = (k + j + 1) &^ j {
+			t := (a[k] ^ (a[k+j] >> j)) & m
+			a[k] ^= t
+			a[k+j] ^= t << j
+		}
+		j >>= 1
+		m ^= m << j
+	}
+}
+
+func anfTerms(anf [16]byte) []uint8 {
+	terms := make([]uint8, 0, 16)
+	for m, coef := range anf {
+		if coef == 1 {
+			terms = append(terms, uint8(m))
+		}
+	}
+	return terms
+}
+
+// mulMatrix 给出 x -> k·x 在 GF(2) 上的 4x4 矩阵：第 o 行为输出位 o 依赖的输入位掩码。
+func mulMatrix(c *Cipher, k byte) [4]uint8 {
+	var rows [4]uint8
+	for i := 0; i < 4; i++ {
+		col := c.mul[k][1<<i]
+		for o := 0; o < 4; o++ {
+			if col&(1<<o) != 0 {
+				rows[o] |= 1 << i
+			}
+		}
+	}
+	return rows
+}
+
+func batchBlock(blocks []uint16, i int) uint16 {
+	if len(blocks) == 1 {
+		return blocks[0]
+	}
+	return blocks[i]
+}
+
+func checkBatch(blocks, keys, out []uint16) {
+	if len(blocks) != 1 && len(blocks) != len(keys) {
+		panic(fmt.Sprintf("saes: 分组数 %d 与密钥数 %d 不一致", len(blocks), len(keys)))
+	}
+	if len(out) < len(keys) {
+		panic("saes: 输出缓冲区过短")
+	}
+}
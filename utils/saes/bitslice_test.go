@@ -0,0 +1,67 @@
+package saes
+
+import "testing"
+
+func TestBitslicedMatchesReference(t *testing.T) {
+	variants := []Params{DefaultParams(), DefaultParams(), DefaultParams()}
+	variants[1].Rounds = 1
+	variants[2].Rounds = 4
+	variants[2].FinalMixColumns = true
+	variants[2].SBox = [16]byte{0x6, 0x4, 0xC, 0x5, 0x0, 0x7, 0x2, 0xE, 0x1, 0xF, 0x3, 0xD, 0x8, 0xA, 0x9, 0xB}
+
+	for _, p := range variants {
+		c := mustNewCipher(p)
+		b := NewBitslicedCipher(c)
+
+		keys := make([]uint16, 0, 1000)
+		blocks := make([]uint16, 0, 1000)
+		for i := 0; i < 1000; i++ {
+			keys = append(keys, uint16(i*7919))
+			blocks = append(blocks, uint16(i*104729))
+		}
+		enc := make([]uint16, len(keys))
+		dec := make([]uint16, len(keys))
+		b.EncryptBatch(blocks, keys, enc)
+		b.DecryptBatch(enc, keys, dec)
+		for i := range keys {
+			if want := c.EncryptBlock(blocks[i], keys[i]); enc[i] != want {
+				t.Fatalf("rounds=%d: EncryptBatch lane %d = %04X, want %04X", p.Rounds, i, enc[i], want)
+			}
+			if dec[i] != blocks[i] {
+				t.Fatalf("rounds=%d: DecryptBatch lane %d = %04X, want %04X", p.Rounds, i, dec[i], blocks[i])
+			}
+		}
+	}
+}
+
+func TestEncryptBatchBroadcast(t *testing.T) {
+	keys := make([]uint16, 1<<16)
+	for k := range keys {
+		keys[k] = uint16(k)
+	}
+	out := make([]uint16, len(keys))
+	EncryptBatch([]uint16{0x6F6B}, keys, out)
+	for k, c := range out {
+		if want := encryptBlock(0x6F6B, uint16(k)); c != want {
+			t.Fatalf("key %04X: got %04X, want %04X", k, c, want)
+		}
+	}
+}
+
+func BenchmarkEncryptBatch(b *testing.B) {
+	keys := make([]uint16, 1<<16)
+	for k := range keys {
+		keys[k] = uint16(k)
+	}
+	out := make([]uint16, len(keys))
+	b.Run("bitslice", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			EncryptBatch([]uint16{0x1234}, keys, out)
+		}
+	})
+	b.Run("fast", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			EncryptBatchWith(Fast, []uint16{0x1234}, keys, out)
+		}
+	})
+}
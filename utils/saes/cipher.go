@@ -0,0 +1,394 @@
+package saes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BlockCipher 抽象单个 16 位密钥下的分组加解密，便于替换 S 盒、既约多项式等参数。
+type BlockCipher interface {
+	EncryptBlock(block, key uint16) uint16
+	DecryptBlock(block, key uint16) uint16
+}
+
+type standardCipher struct{}
+
+func (standardCipher) EncryptBlock(block, key uint16) uint16 { return encryptBlock(block, key) }
+func (standardCipher) DecryptBlock(block, key uint16) uint16 { return decryptBlock(block, key) }
+
+// Standard 为教材标准 S-AES 的参考实现。
+var Standard BlockCipher = standardCipher{}
+
+const (
+	// StandardPoly 为标准 S-AES 使用的既约多项式 x^4+x+1。
+	StandardPoly byte = 0x13
+)
+
+// Params 描述一个（可能被削弱的）S-AES 变体。
+type Params struct {
+	SBox [16]byte
+	// Poly 为 GF(2^4) 的模多项式，包含 x^4 项，例如 0x13 表示 x^4+x+1。
+	Poly byte
+	// MixColumns 为 2x2 列混淆矩阵，按行优先存放 {m00, m01, m10, m11}。
+	MixColumns [4]byte
+	// Rounds 为轮数，标准 S-AES 为 2 轮。
+	Rounds int
+	// FinalMixColumns 为 true 时最后一轮同样执行列混淆（标准 S-AES 省略）。
+	FinalMixColumns bool
+}
+
+// MaxRounds 为 NewCipher 接受的最大轮数。
+const MaxRounds = 32
+
+// DefaultParams 返回标准 S-AES 的参数。
+func DefaultParams() Params {
+	return Params{
+		SBox:       sBox,
+		Poly:       StandardPoly,
+		MixColumns: [4]byte{0x1, 0x4, 0x4, 0x1},
+		Rounds:     2,
+	}
+}
+
+// Cipher 是按 Params 构造的通用 S-AES 实现。
+type Cipher struct {
+	params  Params
+	sBox    [16]byte
+	invSBox [16]byte
+	mix     [4]byte
+	invMix  [4]byte
+	mul     [16][16]byte
+	rcon    []byte
+}
+
+// NewCipher 校验参数并预计算逆 S 盒、逆列混淆矩阵与轮常数。
+func NewCipher(p Params) (*Cipher, error) {
+	for i := range p.SBox {
+		p.SBox[i] &= 0x0F
+	}
+	for i := range p.MixColumns {
+		p.MixColumns[i] &= 0x0F
+	}
+	if p.Rounds < 1 || p.Rounds > MaxRounds {
+		return nil, fmt.Errorf("轮数必须在 1 到 %d 之间", MaxRounds)
+	}
+	if !IsIrreducible(p.Poly) {
+		return nil, fmt.Errorf("多项式 %s 不是 GF(2) 上的 4 次既约多项式", FormatPoly(p.Poly))
+	}
+	inv, ok := invertSBox(p.SBox)
+	if !ok {
+		return nil, fmt.Errorf("S 盒不是双射，无法解密")
+	}
+
+	c := &Cipher{params: p, sBox: p.SBox, invSBox: inv, mix: p.MixColumns, rcon: make([]byte, p.Rounds)}
+	for a := 0; a < 16; a++ {
+		for b := 0; b < 16; b++ {
+			c.mul[a][b] = gfMulPoly(byte(a), byte(b), p.Poly)
+		}
+	}
+
+	m := p.MixColumns
+	det := c.mul[m[0]][m[3]] ^ c.mul[m[1]][m[2]]
+	if det == 0 {
+		return nil, fmt.Errorf("列混淆矩阵在 GF(2^4) 上不可逆")
+	}
+	detInv := c.inverse(det)
+	c.invMix = [4]byte{
+		c.mul[detInv][m[3]],
+		c.mul[detInv][m[1]],
+		c.mul[detInv][m[2]],
+		c.mul[detInv][m[0]],
+	}
+
+	// 轮常数 RC_i = x^(i+2) mod poly（i 从 1 开始），标准参数下前两轮即 0x80、0x30。
+	x := byte(0x2)
+	for i := range c.rcon {
+		c.rcon[i] = c.pow(x, i+3) << 4
+	}
+	return c, nil
+}
+
+// Params 返回构造该实现所用的参数。
+func (c *Cipher) Params() Params {
+	return c.params
+}
+
+// Rounds 返回轮数。
+func (c *Cipher) Rounds() int {
+	return c.params.Rounds
+}
+
+// RoundConstants 返回密钥扩展使用的各轮常数（8 位，高半字节有效）。
+func (c *Cipher) RoundConstants() []byte {
+	return append([]byte(nil), c.rcon...)
+}
+
+// EncryptBlock 使用该变体加密一个 16 位分组。
+func (c *Cipher) EncryptBlock(block, key uint16) uint16 {
+	roundKeys := c.expandKey(key)
+	state := uint16ToStateCore(block)
+
+	state = addRoundKeyCore(state, roundKeys[0])
+	for r := 1; r <= c.params.Rounds; r++ {
+		state = subNibCore(state, c.sBox)
+		state = shiftRowsCore(state)
+		if c.mixesIn(r) {
+			state = c.mixColumns(state, c.mix)
+		}
+		state = addRoundKeyCore(state, roundKeys[r])
+	}
+
+	return stateToUint16(state)
+}
+
+// DecryptBlock 使用该变体解密一个 16 位分组。
+func (c *Cipher) DecryptBlock(block, key uint16) uint16 {
+	roundKeys := c.expandKey(key)
+	state := uint16ToStateCore(block)
+
+	for r := c.params.Rounds; r >= 1; r-- {
+		state = addRoundKeyCore(state, roundKeys[r])
+		if c.mixesIn(r) {
+			state = c.mixColumns(state, c.invMix)
+		}
+		state = invShiftRowsCore(state)
+		state = subNibCore(state, c.invSBox)
+	}
+	state = addRoundKeyCore(state, roundKeys[0])
+
+	return stateToUint16(state)
+}
+
+// mixesIn 报告第 r 轮（从 1 开始）是否执行列混淆。
+func (c *Cipher) mixesIn(r int) bool {
+	return r < c.params.Rounds || c.params.FinalMixColumns
+}
+
+func (c *Cipher) g(word, rcon byte) byte {
+	w := rotNib(word)
+	out := (c.sBox[(w>>4)&0x0F] << 4) | c.sBox[w&0x0F]
+	return out ^ rcon
+}
+
+// expandKey 生成 Rounds+1 个轮密钥：w[2i] = w[2i-2] ^ g(w[2i-1], RC_i)，w[2i+1] = w[2i] ^ w[2i-1]。
+func (c *Cipher) expandKey(key uint16) []roundKeyCore {
+	roundKeys := make([]roundKeyCore, c.params.Rounds+1)
+	even := byte((key >> 8) & 0xFF)
+	odd := byte(key & 0xFF)
+	roundKeys[0] = wordPairToRoundKeyCore(even, odd)
+
+	for i := 1; i <= c.params.Rounds; i++ {
+		even ^= c.g(odd, c.rcon[i-1])
+		odd ^= even
+		roundKeys[i] = wordPairToRoundKeyCore(even, odd)
+	}
+	return roundKeys
+}
+
+func (c *Cipher) mixColumns(state state4, m [4]byte) state4 {
+	a, b := state[0], state[1]
+	d, e := state[2], state[3]
+	return state4{
+		c.mul[m[0]][a] ^ c.mul[m[1]][b],
+		c.mul[m[2]][a] ^ c.mul[m[3]][b],
+		c.mul[m[0]][d] ^ c.mul[m[1]][e],
+		c.mul[m[2]][d] ^ c.mul[m[3]][e],
+	}
+}
+
+func (c *Cipher) pow(a byte, n int) byte {
+	res := byte(0x1)
+	for i := 0; i < n; i++ {
+		res = c.mul[res][a]
+	}
+	return res
+}
+
+func (c *Cipher) inverse(a byte) byte {
+	for b := byte(1); b < 16; b++ {
+		if c.mul[a][b] == 1 {
+			return b
+		}
+	}
+	return 0
+}
+
+// gfMulPoly 在以 poly 为模的 GF(2^4) 上做乘法。
+func gfMulPoly(a, b, poly byte) byte {
+	var res byte
+	x := a & 0x0F
+	y := b & 0x0F
+
+	for i := 0; i < 4; i++ {
+		if (y & 0x1) != 0 {
+			res ^= x
+		}
+
+		overflow := (x & 0x8) != 0
+		x = (x << 1) & 0x0F
+		if overflow {
+			x ^= poly & 0x0F
+		}
+
+		y >>= 1
+	}
+	return res & 0x0F
+}
+
+func invertSBox(box [16]byte) ([16]byte, bool) {
+	var inv [16]byte
+	var seen [16]bool
+	for x, y := range box {
+		if seen[y] {
+			return inv, false
+		}
+		seen[y] = true
+		inv[y] = byte(x)
+	}
+	return inv, true
+}
+
+// IsIrreducible 判断 poly 是否为 GF(2) 上的 4 次既约多项式。
+func IsIrreducible(poly byte) bool {
+	if poly&0xF0 != 0x10 {
+		return false
+	}
+	// 4 次多项式可约当且仅当含有 1 次或 2 次因式。
+	for d := byte(0x2); d < 0x8; d++ {
+		if polyMod(poly, d) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func polyMod(a, b byte) byte {
+	degB := bitLen(b) - 1
+	for bitLen(a)-1 >= degB && a != 0 {
+		a ^= b << (bitLen(a) - 1 - degB)
+	}
+	return a
+}
+
+func bitLen(v byte) int {
+	n := 0
+	for v != 0 {
+		n++
+		v >>= 1
+	}
+	return n
+}
+
+// FormatPoly 将多项式格式化为 x^4+x+1 形式。
+func FormatPoly(poly byte) string {
+	terms := make([]string, 0, 5)
+	for i := 7; i >= 0; i-- {
+		if poly&(1<<i) == 0 {
+			continue
+		}
+		switch i {
+		case 0:
+			terms = append(terms, "1")
+		case 1:
+			terms = append(terms, "x")
+		default:
+			terms = append(terms, fmt.Sprintf("x^%d", i))
+		}
+	}
+	if len(terms) == 0 {
+		return "0"
+	}
+	return strings.Join(terms, "+")
+}
+
+// ParseSBox 解析 16 个十六进制半字节组成的 S 盒，支持 "94ABD1856203CEF7" 或以空格/逗号分隔的写法。
+func ParseSBox(input string) ([16]byte, error) {
+	var box [16]byte
+	fields := splitNibbleFields(input)
+	if len(fields) == 1 {
+		compact := strings.TrimPrefix(strings.ToLower(fields[0]), "0x")
+		if len(compact) != 16 {
+			return box, fmt.Errorf("S 盒必须包含 16 个十六进制半字节")
+		}
+		fields = strings.Split(compact, "")
+	}
+	if len(fields) != 16 {
+		return box, fmt.Errorf("S 盒必须包含 16 个十六进制半字节")
+	}
+	for i, f := range fields {
+		v, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(f), "0x"), 16, 4)
+		if err != nil {
+			return box, fmt.Errorf("S 盒第 %d 项无法解析: %w", i, err)
+		}
+		box[i] = byte(v)
+	}
+	return box, nil
+}
+
+// ParsePoly 解析模多项式，支持十六进制（如 0x13）或 5 位二进制（如 10011）。
+func ParsePoly(input string) (byte, error) {
+	sanitized := sanitizeBinaryString(input)
+	var (
+		v   uint64
+		err error
+	)
+	if strings.HasPrefix(strings.ToLower(sanitized), "0x") {
+		v, err = strconv.ParseUint(sanitized[2:], 16, 8)
+	} else {
+		v, err = strconv.ParseUint(sanitized, 2, 8)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("无法解析多项式: %w", err)
+	}
+	if byte(v)&0xF0 != 0x10 {
+		return 0, fmt.Errorf("多项式必须是 4 次（最高位为 x^4）")
+	}
+	return byte(v), nil
+}
+
+// ParseMixColumns 解析 2x2 列混淆矩阵，按行优先给出 4 个十六进制半字节，如 "1441"。
+func ParseMixColumns(input string) ([4]byte, error) {
+	var m [4]byte
+	fields := splitNibbleFields(input)
+	if len(fields) == 1 {
+		compact := strings.TrimPrefix(strings.ToLower(fields[0]), "0x")
+		fields = strings.Split(compact, "")
+	}
+	if len(fields) != 4 {
+		return m, fmt.Errorf("列混淆矩阵必须包含 4 个十六进制半字节")
+	}
+	for i, f := range fields {
+		v, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(f), "0x"), 16, 4)
+		if err != nil {
+			return m, fmt.Errorf("列混淆矩阵第 %d 项无法解析: %w", i, err)
+		}
+		m[i] = byte(v)
+	}
+	return m, nil
+}
+
+func splitNibbleFields(input string) []string {
+	return strings.FieldsFunc(strings.TrimSpace(input), func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+}
+
+// Engines 列出 NewEngine 支持的实现名称。
+var Engines = []string{"reference", "fast", "bitslice", "constant-time"}
+
+// NewEngine 按名称为 c 构造分组实现；空名称等同于 "reference"，即 c 本身。
+func NewEngine(name string, c *Cipher) (BlockCipher, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "reference":
+		return c, nil
+	case "fast":
+		return NewFastCipher(c), nil
+	case "bitslice":
+		return NewBitslicedCipher(c), nil
+	case "constant-time":
+		return NewConstantTimeCipher(c), nil
+	default:
+		return nil, fmt.Errorf("不支持的实现: %s（可选 %s）", name, strings.Join(Engines, "、"))
+	}
+}
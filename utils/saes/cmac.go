@@ -0,0 +1,75 @@
+package saes
+
+import "hash"
+
+// cmacPoly 为 GF(2^16) 的既约多项式 x^16 + x^5 + x^3 + x^2 + 1 去掉最高项后的低 16 位，
+// 用于由 L = E(0) 推导 CMAC 子密钥。
+const cmacPoly = 0x002D
+
+// CMACSize 为 S-AES CMAC 标签的字节数（一个分组）。
+const CMACSize = 2
+
+// cmac 按 NIST SP 800-38B 的结构实现 16 位分组上的 CMAC。
+type cmac struct {
+	kb     *KeyedBlock
+	k1, k2 uint16
+	state  uint16
+	// pending 为尚未处理的末尾字节，最后一个分组要等到 Sum 时才能确定使用哪个子密钥。
+	pending []byte
+}
+
+// NewCMAC 返回以 kb 为密钥的 CMAC，实现 hash.Hash。16 位标签只适合教学演示，不能抵御穷举伪造。
+func NewCMAC(kb *KeyedBlock) hash.Hash {
+	l := kb.EncryptBlock(0)
+	k1 := dbl16(l)
+	return &cmac{kb: kb, k1: k1, k2: dbl16(k1)}
+}
+
+func dbl16(v uint16) uint16 {
+	if v&0x8000 != 0 {
+		return v<<1 ^ cmacPoly
+	}
+	return v << 1
+}
+
+func (m *cmac) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		if len(m.pending) == 2 {
+			m.state = m.kb.EncryptBlock(m.state ^ getBlock(m.pending))
+			m.pending = m.pending[:0]
+		}
+		take := min(2-len(m.pending), len(p))
+		m.pending = append(m.pending, p[:take]...)
+		p = p[take:]
+	}
+	return n, nil
+}
+
+func (m *cmac) Sum(b []byte) []byte {
+	var last [2]byte
+	copy(last[:], m.pending)
+	block := getBlock(last[:])
+	if len(m.pending) == 2 {
+		block ^= m.k1
+	} else {
+		// 不完整的分组补 10*。
+		last[len(m.pending)] = 0x80
+		block = getBlock(last[:]) ^ m.k2
+	}
+	tag := m.kb.EncryptBlock(m.state ^ block)
+	return append(b, byte(tag>>8), byte(tag))
+}
+
+func (m *cmac) Reset() {
+	m.state = 0
+	m.pending = m.pending[:0]
+}
+
+func (m *cmac) Size() int {
+	return CMACSize
+}
+
+func (m *cmac) BlockSize() int {
+	return 2
+}
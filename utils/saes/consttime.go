@@ -0,0 +1,129 @@
+package saes
+
+// 常量时间实现：S 盒以掩码扫描全部 16 项代替按秘密值下标查表，
+// GF(2^4) 乘法以掩码代替 gfMulCore 中依赖秘密位的分支。
+
+// ConstantTimeCipher 是与 Cipher 逐位等价、执行路径与访存模式不依赖秘密数据的实现。
+type ConstantTimeCipher struct {
+	sBox     [16]byte
+	invSBox  [16]byte
+	poly     byte
+	mix      [4]byte
+	invMix   [4]byte
+	rcon     []byte
+	rounds   int
+	finalMix bool
+}
+
+// ConstantTime 为标准 S-AES 的常量时间实现。
+var ConstantTime = NewConstantTimeCipher(standardGeneric)
+
+// NewConstantTimeCipher 由 Cipher 的参数构造常量时间实现。
+func NewConstantTimeCipher(c *Cipher) *ConstantTimeCipher {
+	return &ConstantTimeCipher{
+		sBox:     c.sBox,
+		invSBox:  c.invSBox,
+		poly:     c.params.Poly,
+		mix:      c.mix,
+		invMix:   c.invMix,
+		rcon:     c.RoundConstants(),
+		rounds:   c.params.Rounds,
+		finalMix: c.params.FinalMixColumns,
+	}
+}
+
+// EncryptBlock 实现 BlockCipher。
+func (ct *ConstantTimeCipher) EncryptBlock(block, key uint16) uint16 {
+	var roundKeys [MaxRounds + 1]roundKeyCore
+	ct.expandKey(key, &roundKeys)
+	state := uint16ToStateCore(block)
+
+	state = addRoundKeyCore(state, roundKeys[0])
+	for r := 1; r <= ct.rounds; r++ {
+		state = ct.subNib(state, &ct.sBox)
+		state = shiftRowsCore(state)
+		if r < ct.rounds || ct.finalMix {
+			state = ct.mixColumns(state, ct.mix)
+		}
+		state = addRoundKeyCore(state, roundKeys[r])
+	}
+	return stateToUint16(state)
+}
+
+// DecryptBlock 实现 BlockCipher。
+func (ct *ConstantTimeCipher) DecryptBlock(block, key uint16) uint16 {
+	var roundKeys [MaxRounds + 1]roundKeyCore
+	ct.expandKey(key, &roundKeys)
+	state := uint16ToStateCore(block)
+
+	for r := ct.rounds; r >= 1; r-- {
+		state = addRoundKeyCore(state, roundKeys[r])
+		if r < ct.rounds || ct.finalMix {
+			state = ct.mixColumns(state, ct.invMix)
+		}
+		state = invShiftRowsCore(state)
+		state = ct.subNib(state, &ct.invSBox)
+	}
+	state = addRoundKeyCore(state, roundKeys[0])
+	return stateToUint16(state)
+}
+
+func (ct *ConstantTimeCipher) expandKey(key uint16, roundKeys *[MaxRounds + 1]roundKeyCore) {
+	even := byte(key >> 8)
+	odd := byte(key)
+	roundKeys[0] = wordPairToRoundKeyCore(even, odd)
+	for i := 1; i <= ct.rounds; i++ {
+		w := rotNib(odd)
+		g := ct.lookup(&ct.sBox, w>>4)<<4 | ct.lookup(&ct.sBox, w&0x0F)
+		even ^= g ^ ct.rcon[i-1]
+		odd ^= even
+		roundKeys[i] = wordPairToRoundKeyCore(even, odd)
+	}
+}
+
+func (ct *ConstantTimeCipher) subNib(state state4, box *[16]byte) state4 {
+	for i, v := range state {
+		state[i] = ct.lookup(box, v)
+	}
+	return state
+}
+
+// lookup 读取 box 的全部 16 项，仅用掩码保留下标为 x 的一项。
+func (ct *ConstantTimeCipher) lookup(box *[16]byte, x byte) byte {
+	var out byte
+	for v := byte(0); v < 16; v++ {
+		out |= box[v] & ctEqMask(x&0x0F, v)
+	}
+	return out
+}
+
+func (ct *ConstantTimeCipher) mixColumns(state state4, m [4]byte) state4 {
+	a, b := state[0], state[1]
+	d, e := state[2], state[3]
+	return state4{
+		ct.gfMul(m[0], a) ^ ct.gfMul(m[1], b),
+		ct.gfMul(m[2], a) ^ ct.gfMul(m[3], b),
+		ct.gfMul(m[0], d) ^ ct.gfMul(m[1], e),
+		ct.gfMul(m[2], d) ^ ct.gfMul(m[3], e),
+	}
+}
+
+// gfMul 与 gfMulPoly 结果相同，但不对任何输入位分支。
+func (ct *ConstantTimeCipher) gfMul(a, b byte) byte {
+	var res byte
+	x := a & 0x0F
+	y := b & 0x0F
+	for i := 0; i < 4; i++ {
+		res ^= x & -(y & 0x1)
+		overflow := -((x >> 3) & 0x1)
+		x = ((x << 1) ^ (ct.poly & overflow)) & 0x0F
+		y >>= 1
+	}
+	return res
+}
+
+// ctEqMask 在 a == b 时返回 0xFF，否则返回 0x00。
+func ctEqMask(a, b byte) byte {
+	diff := uint32(a ^ b)
+	return byte((diff - 1) >> 8)
+}
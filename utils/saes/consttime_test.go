@@ -0,0 +1,42 @@
+package saes
+
+import "testing"
+
+func TestConstantTimeMatchesStandard(t *testing.T) {
+	for key := 0; key <= 0xFFFF; key += 7 {
+		for block := 0; block <= 0xFFFF; block += 257 {
+			want := Standard.EncryptBlock(uint16(block), uint16(key))
+			if got := ConstantTime.EncryptBlock(uint16(block), uint16(key)); got != want {
+				t.Fatalf("EncryptBlock(%04X, %04X) = %04X, want %04X", block, key, got, want)
+			}
+			if got := ConstantTime.DecryptBlock(want, uint16(key)); got != uint16(block) {
+				t.Fatalf("DecryptBlock(%04X, %04X) = %04X, want %04X", want, key, got, block)
+			}
+		}
+	}
+}
+
+func TestConstantTimeMatchesParameterizedCipher(t *testing.T) {
+	variants := []Params{DefaultParams(), DefaultParams(), DefaultParams()}
+	variants[1].Rounds = 4
+	variants[1].FinalMixColumns = true
+	variants[2].Rounds = 3
+	variants[2].Poly = 0x19
+	variants[2].MixColumns = [4]byte{0x1, 0x2, 0x3, 0x1}
+
+	for _, p := range variants {
+		c := mustNewCipher(p)
+		ct := NewConstantTimeCipher(c)
+		for key := 0; key <= 0xFFFF; key += 331 {
+			for block := 0; block <= 0xFFFF; block += 263 {
+				want := c.EncryptBlock(uint16(block), uint16(key))
+				if got := ct.EncryptBlock(uint16(block), uint16(key)); got != want {
+					t.Fatalf("rounds=%d: EncryptBlock(%04X, %04X) = %04X, want %04X", p.Rounds, block, key, got, want)
+				}
+				if got := ct.DecryptBlock(want, uint16(key)); got != uint16(block) {
+					t.Fatalf("rounds=%d: DecryptBlock(%04X, %04X) = %04X, want %04X", p.Rounds, want, key, got, block)
+				}
+			}
+		}
+	}
+}
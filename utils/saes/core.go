@@ -160,6 +160,13 @@ func DecryptBlockRaw(block, key uint16) uint16 {
 	return decryptBlockCore(block, key)
 }
 
+// RawKey 以 16 位密钥直接调用 EncryptBlockRaw，可在只需加密单个分组的场合代替 *KeyedBlock。
+type RawKey uint16
+
+func (k RawKey) EncryptBlock(block uint16) uint16 {
+	return encryptBlockCore(block, uint16(k))
+}
+
 func DoubleEncryptRaw(block, k1, k2 uint16) uint16 {
 	return doubleEncryptCore(block, k1, k2)
 }
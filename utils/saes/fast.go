@@ -0,0 +1,147 @@
+package saes
+
+// 查表实现：把 SubNib+ShiftRows+MixColumns 合并为按字节索引的 T 表，
+// 轮密钥以 uint16 形式预先展开，供中间相遇攻击等热点循环使用。
+
+// FastCipher 是与 Cipher 逐位等价的查表实现。
+type FastCipher struct {
+	rounds   int
+	finalMix bool
+	rcon     []byte
+	// gTab[w] = SubNib(RotNib(w))，用于密钥扩展。
+	gTab [256]byte
+
+	// 加密：roundHi/Lo 为带列混淆的轮函数，lastHi/Lo 为最后一轮（是否列混淆取决于 finalMix）。
+	roundHi, roundLo [256]uint16
+	lastHi, lastLo   [256]uint16
+	// 解密：invRoundHi/Lo 合并 InvMixColumns+InvShiftRows+InvSubNib，invLastHi/Lo 省略列混淆。
+	invRoundHi, invRoundLo [256]uint16
+	invLastHi, invLastLo   [256]uint16
+}
+
+// KeySchedule 为预先展开的轮密钥（按 16 位状态排列）。
+type KeySchedule struct {
+	keys [MaxRounds + 1]uint16
+	n    int
+}
+
+// Fast 为标准 S-AES 的查表实现。
+var Fast = NewFastCipher(standardGeneric)
+
+// NewFastCipher 由 Cipher 的参数构造查表实现。
+func NewFastCipher(c *Cipher) *FastCipher {
+	f := &FastCipher{
+		rounds:   c.params.Rounds,
+		finalMix: c.params.FinalMixColumns,
+		rcon:     c.RoundConstants(),
+	}
+
+	for w := 0; w < 256; w++ {
+		f.gTab[w] = c.g(byte(w), 0)
+	}
+
+	for b := 0; b < 256; b++ {
+		hi, lo := byte(b>>4), byte(b&0x0F)
+
+		// 高字节为第 0 列 (n0, n1)，ShiftRows 后 n0 留在位置 0，n1 移到位置 3；
+		// 低字节为第 1 列 (n2, n3)，n2 留在位置 2，n3 移到位置 1。
+		fromHi := state4{c.sBox[hi], 0, 0, c.sBox[lo]}
+		fromLo := state4{0, c.sBox[lo], c.sBox[hi], 0}
+		f.roundHi[b] = stateToUint16(c.mixColumns(fromHi, c.mix))
+		f.roundLo[b] = stateToUint16(c.mixColumns(fromLo, c.mix))
+		f.lastHi[b], f.lastLo[b] = stateToUint16(fromHi), stateToUint16(fromLo)
+		if f.finalMix {
+			f.lastHi[b], f.lastLo[b] = f.roundHi[b], f.roundLo[b]
+		}
+
+		// 逆列混淆只在列内作用，因此可与 InvShiftRows、InvSubNib 合并到同一张表。
+		col := c.mixColumns(state4{hi, lo, 0, 0}, c.invMix)
+		f.invRoundHi[b] = stateToUint16(state4{c.invSBox[col[0]], 0, 0, c.invSBox[col[1]]})
+		col = c.mixColumns(state4{0, 0, hi, lo}, c.invMix)
+		f.invRoundLo[b] = stateToUint16(state4{0, c.invSBox[col[3]], c.invSBox[col[2]], 0})
+
+		f.invLastHi[b] = stateToUint16(state4{c.invSBox[hi], 0, 0, c.invSBox[lo]})
+		f.invLastLo[b] = stateToUint16(state4{0, c.invSBox[lo], c.invSBox[hi], 0})
+	}
+	if f.finalMix {
+		f.invLastHi, f.invLastLo = f.invRoundHi, f.invRoundLo
+	}
+	return f
+}
+
+// ExpandKey 预先展开全部轮密钥。
+func (f *FastCipher) ExpandKey(key uint16) KeySchedule {
+	ks := KeySchedule{n: f.rounds + 1}
+	even := byte(key >> 8)
+	odd := byte(key)
+	ks.keys[0] = key
+	for i := 1; i <= f.rounds; i++ {
+		even ^= f.gTab[odd] ^ f.rcon[i-1]
+		odd ^= even
+		ks.keys[i] = uint16(even)<<8 | uint16(odd)
+	}
+	return ks
+}
+
+// EncryptWithSchedule 使用预展开的轮密钥加密一个分组。
+func (f *FastCipher) EncryptWithSchedule(block uint16, ks *KeySchedule) uint16 {
+	s := block ^ ks.keys[0]
+	last := f.rounds
+	for r := 1; r < last; r++ {
+		s = f.roundHi[s>>8] ^ f.roundLo[s&0xFF] ^ ks.keys[r]
+	}
+	return f.lastHi[s>>8] ^ f.lastLo[s&0xFF] ^ ks.keys[last]
+}
+
+// DecryptWithSchedule 使用预展开的轮密钥解密一个分组。
+func (f *FastCipher) DecryptWithSchedule(block uint16, ks *KeySchedule) uint16 {
+	s := block ^ ks.keys[f.rounds]
+	s = f.invLastHi[s>>8] ^ f.invLastLo[s&0xFF]
+	for r := f.rounds - 1; r >= 1; r-- {
+		s ^= ks.keys[r]
+		s = f.invRoundHi[s>>8] ^ f.invRoundLo[s&0xFF]
+	}
+	return s ^ ks.keys[0]
+}
+
+// EncryptBlock 实现 BlockCipher。
+func (f *FastCipher) EncryptBlock(block, key uint16) uint16 {
+	ks := f.ExpandKey(key)
+	return f.EncryptWithSchedule(block, &ks)
+}
+
+// DecryptBlock 实现 BlockCipher。
+func (f *FastCipher) DecryptBlock(block, key uint16) uint16 {
+	ks := f.ExpandKey(key)
+	return f.DecryptWithSchedule(block, &ks)
+}
+
+// Codebook 预计算某个密钥下全部 2^16 个明文对应的密文。
+func (f *FastCipher) Codebook(key uint16) *[1 << 16]uint16 {
+	ks := f.ExpandKey(key)
+	table := new([1 << 16]uint16)
+	for p := range table {
+		table[p] = f.EncryptWithSchedule(uint16(p), &ks)
+	}
+	return table
+}
+
+// Accelerate 返回与 bc 逐位等价的查表实现；无法识别的实现原样返回。
+func Accelerate(bc BlockCipher) BlockCipher {
+	switch c := bc.(type) {
+	case standardCipher:
+		return Fast
+	case *Cipher:
+		return NewFastCipher(c)
+	default:
+		return bc
+	}
+}
+
+func mustNewCipher(p Params) *Cipher {
+	c, err := NewCipher(p)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
@@ -0,0 +1,78 @@
+package saes
+
+import "testing"
+
+func TestFastMatchesReference(t *testing.T) {
+	for key := 0; key <= 0xFFFF; key += 7 {
+		ks := Fast.ExpandKey(uint16(key))
+		for block := 0; block <= 0xFFFF; block += 257 {
+			want := encryptBlock(uint16(block), uint16(key))
+			if got := Fast.EncryptWithSchedule(uint16(block), &ks); got != want {
+				t.Fatalf("EncryptWithSchedule(%04X, %04X) = %04X, want %04X", block, key, got, want)
+			}
+			if got := Fast.DecryptWithSchedule(want, &ks); got != uint16(block) {
+				t.Fatalf("DecryptWithSchedule(%04X, %04X) = %04X, want %04X", want, key, got, block)
+			}
+		}
+	}
+}
+
+func TestFastMatchesParameterizedCipher(t *testing.T) {
+	variants := []Params{DefaultParams(), DefaultParams(), DefaultParams(), DefaultParams()}
+	variants[1].Rounds = 1
+	variants[2].Rounds = 4
+	variants[2].FinalMixColumns = true
+	variants[3].Rounds = 8
+	variants[3].Poly = 0x19
+	variants[3].MixColumns = [4]byte{0x1, 0x2, 0x3, 0x1}
+
+	for _, p := range variants {
+		c := mustNewCipher(p)
+		f := NewFastCipher(c)
+		for key := 0; key <= 0xFFFF; key += 331 {
+			for block := 0; block <= 0xFFFF; block += 263 {
+				want := c.EncryptBlock(uint16(block), uint16(key))
+				if got := f.EncryptBlock(uint16(block), uint16(key)); got != want {
+					t.Fatalf("rounds=%d: EncryptBlock(%04X, %04X) = %04X, want %04X", p.Rounds, block, key, got, want)
+				}
+				if got := f.DecryptBlock(want, uint16(key)); got != uint16(block) {
+					t.Fatalf("rounds=%d: DecryptBlock(%04X, %04X) = %04X, want %04X", p.Rounds, want, key, got, block)
+				}
+			}
+		}
+	}
+}
+
+func TestCodebook(t *testing.T) {
+	const key = 0xA73B
+	table := Fast.Codebook(key)
+	for p := range table {
+		if want := encryptBlock(uint16(p), key); table[p] != want {
+			t.Fatalf("Codebook[%04X] = %04X, want %04X", p, table[p], want)
+		}
+	}
+}
+
+func BenchmarkEncryptBlock(b *testing.B) {
+	b.Run("reference", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			encryptBlock(uint16(i), uint16(i>>3))
+		}
+	})
+	b.Run("core", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			encryptBlockCore(uint16(i), uint16(i>>3))
+		}
+	})
+	b.Run("fast", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Fast.EncryptBlock(uint16(i), uint16(i>>3))
+		}
+	})
+	b.Run("fast-schedule", func(b *testing.B) {
+		ks := Fast.ExpandKey(0xA73B)
+		for i := 0; i < b.N; i++ {
+			Fast.EncryptWithSchedule(uint16(i), &ks)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package saes
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+)
+
+// 密钥包装把 RFC 3394（KW）与 RFC 5649（KWP）移植到 16 位分组：分组拆成两个 8 位半块，
+// 高半块为完整性寄存器 A，低半块为一字节密钥材料 R[i]。与原算法一样做 6n 次分组加密，
+// 每步把计数 t 的低 8 位异或进 A。
+//
+// KW 以 0xA6 为初始值，要求至少 2 字节；解包时只有 8 位校验，随机篡改约有 1/256 的概率
+// 通过，仅适合教学演示。KWP 以 0x59 为初始值，并在材料前附加 16 位长度指示（MLI），
+// 支持 1 到 65535 字节的任意长度，校验强度为 24 位。由于半块只有一字节，任何字节长度都是
+// 整数个半块，因此 KWP 不再需要填充。
+const (
+	KeyWrapIV    byte = 0xA6
+	KeyWrapPadIV byte = 0x59
+
+	maxKeyWrapPad = 0xFFFF
+)
+
+// ErrUnwrap 表示解包时完整性校验失败：密钥错误或数据被篡改。
+var ErrUnwrap = errors.New("密钥解包失败：完整性校验未通过")
+
+// WrapKey 按 KW 包装 plaintext（至少 2 字节），输出比输入多 1 字节。
+func WrapKey(kek *KeyedBlock, plaintext []byte) ([]byte, error) {
+	if len(plaintext) < 2 {
+		return nil, fmt.Errorf("KW 要求待包装数据至少 2 字节，较短的数据请使用 KWP")
+	}
+	return wrap(kek, KeyWrapIV, plaintext), nil
+}
+
+// UnwrapKey 解包 WrapKey 的输出，校验失败时返回 ErrUnwrap。
+func UnwrapKey(kek *KeyedBlock, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < 3 {
+		return nil, fmt.Errorf("KW 包装数据至少 3 字节")
+	}
+	a, r := unwrap(kek, ciphertext)
+	if subtle.ConstantTimeByteEq(a, KeyWrapIV) != 1 {
+		return nil, ErrUnwrap
+	}
+	return r, nil
+}
+
+// WrapKeyPad 按 KWP 包装 plaintext（1 到 65535 字节），输出比输入多 3 字节。
+func WrapKeyPad(kek *KeyedBlock, plaintext []byte) ([]byte, error) {
+	if len(plaintext) < 1 || len(plaintext) > maxKeyWrapPad {
+		return nil, fmt.Errorf("KWP 待包装数据长度必须在 1 到 %d 字节之间", maxKeyWrapPad)
+	}
+	r := make([]byte, 2+len(plaintext))
+	putBlock(r, uint16(len(plaintext)))
+	copy(r[2:], plaintext)
+	return wrap(kek, KeyWrapPadIV, r), nil
+}
+
+// UnwrapKeyPad 解包 WrapKeyPad 的输出，初始值或长度指示不符时返回 ErrUnwrap。
+func UnwrapKeyPad(kek *KeyedBlock, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < 4 {
+		return nil, fmt.Errorf("KWP 包装数据至少 4 字节")
+	}
+	a, r := unwrap(kek, ciphertext)
+	ok := subtle.ConstantTimeByteEq(a, KeyWrapPadIV) &
+		subtle.ConstantTimeEq(int32(getBlock(r)), int32(len(r)-2))
+	if ok != 1 {
+		return nil, ErrUnwrap
+	}
+	return r[2:], nil
+}
+
+// wrap 为 RFC 3394 的 W 函数，输出 A || R[1..n]。
+func wrap(kek *KeyedBlock, iv byte, plaintext []byte) []byte {
+	n := len(plaintext)
+	out := make([]byte, 1+n)
+	r := out[1:]
+	copy(r, plaintext)
+	a := iv
+	for j := 0; j < 6; j++ {
+		for i := 0; i < n; i++ {
+			b := kek.EncryptBlock(uint16(a)<<8 | uint16(r[i]))
+			t := n*j + i + 1
+			a = byte(b>>8) ^ byte(t)
+			r[i] = byte(b)
+		}
+	}
+	out[0] = a
+	return out
+}
+
+// unwrap 为 RFC 3394 的逆函数 W^-1，返回恢复出的 A 与 R[1..n]，由调用方校验 A。
+func unwrap(kek *KeyedBlock, ciphertext []byte) (byte, []byte) {
+	n := len(ciphertext) - 1
+	a := ciphertext[0]
+	r := make([]byte, n)
+	copy(r, ciphertext[1:])
+	for j := 5; j >= 0; j-- {
+		for i := n - 1; i >= 0; i-- {
+			t := n*j + i + 1
+			b := kek.DecryptBlock(uint16(a^byte(t))<<8 | uint16(r[i]))
+			a = byte(b >> 8)
+			r[i] = byte(b)
+		}
+	}
+	return a, r
+}
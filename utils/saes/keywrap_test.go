@@ -0,0 +1,123 @@
+package saes
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestKeyWrapVectors(t *testing.T) {
+	// 回归向量：由本实现生成，用于发现输出的意外变化；正确性由 TestKeyWrapByHand 逐步核对。
+	cases := []struct {
+		key, plain, wrapped string
+		pad                 bool
+	}{
+		{"0x4AF5", "0123456789ab", "6fd4f3362cf59a", false},
+		{"0xA73B", "a73b", "dee78a", false},
+		{"0x4AF5", "5e", "efb71b14", true},
+	}
+	for _, tc := range cases {
+		kek, err := NewKeyedBlock(Standard, tc.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		plain, _ := hex.DecodeString(tc.plain)
+		wrapFn, unwrapFn := WrapKey, UnwrapKey
+		if tc.pad {
+			wrapFn, unwrapFn = WrapKeyPad, UnwrapKeyPad
+		}
+		got, err := wrapFn(kek, plain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(got) != tc.wrapped {
+			t.Fatalf("包装 %s = %x，期望 %s", tc.plain, got, tc.wrapped)
+		}
+		back, err := unwrapFn(kek, got)
+		if err != nil || !bytes.Equal(back, plain) {
+			t.Fatalf("解包 %s 失败: %x, %v", tc.wrapped, back, err)
+		}
+	}
+}
+
+func TestKeyWrapByHand(t *testing.T) {
+	// 按 RFC 3394 第 2.2.1 节把 2 字节 KW 的 12 步逐一展开：第 s 步加密 A||R[i]，
+	// A 取结果的高字节并异或 t=s，R[i] 取低字节；i 依次为 1、2、1、2……
+	kek, _ := NewKeyedBlock(Standard, "0x4AF5")
+	chain := func(a byte) []byte {
+		r := [2]byte{0x12, 0x34}
+		for s, i := range [12]int{0, 1, 0, 1, 0, 1, 0, 1, 0, 1, 0, 1} {
+			b := kek.EncryptBlock(uint16(a)<<8 | uint16(r[i]))
+			a, r[i] = byte(b>>8)^byte(s+1), byte(b)
+		}
+		return []byte{a, r[0], r[1]}
+	}
+
+	want := chain(KeyWrapIV)
+	got, err := WrapKey(kek, []byte{0x12, 0x34})
+	if err != nil || !bytes.Equal(got, want) {
+		t.Fatalf("包装 1234 = %x, %v，逐步展开得到 %x", got, err, want)
+	}
+	if back, err := UnwrapKey(kek, want); err != nil || !bytes.Equal(back, []byte{0x12, 0x34}) {
+		t.Fatalf("解包手工构造的 %x = %x, %v", want, back, err)
+	}
+	// 以 0xA7 为初始值手工构造的包装数据，解包后恢复出的 A 不是 0xA6，应被拒绝。
+	forged := chain(KeyWrapIV ^ 1)
+	if a, r := unwrap(kek, forged); a != KeyWrapIV^1 || !bytes.Equal(r, []byte{0x12, 0x34}) {
+		t.Fatalf("W^-1 恢复出 A=%02x、R=%x", a, r)
+	}
+	if _, err := UnwrapKey(kek, forged); !errors.Is(err, ErrUnwrap) {
+		t.Fatalf("初始值不符的包装数据应返回 ErrUnwrap，得到 %v", err)
+	}
+}
+
+func TestKeyWrapIntegrity(t *testing.T) {
+	kek, _ := NewKeyedBlock(Standard, "0x1234")
+	other, _ := NewKeyedBlock(Standard, "0x1235")
+	material := []byte("0123456789")
+
+	for n := 1; n <= len(material); n++ {
+		wrapped, err := WrapKeyPad(kek, material[:n])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(wrapped) != n+3 {
+			t.Fatalf("KWP 输出长度 %d，期望 %d", len(wrapped), n+3)
+		}
+		got, err := UnwrapKeyPad(kek, wrapped)
+		if err != nil || !bytes.Equal(got, material[:n]) {
+			t.Fatalf("KWP %d 字节往返失败: %v", n, err)
+		}
+		if _, err := UnwrapKeyPad(other, wrapped); !errors.Is(err, ErrUnwrap) {
+			t.Fatalf("KWP %d 字节: 错误的 KEK 未被发现", n)
+		}
+		for i := range wrapped {
+			tampered := append([]byte(nil), wrapped...)
+			tampered[i] ^= 0x01
+			if _, err := UnwrapKeyPad(kek, tampered); !errors.Is(err, ErrUnwrap) {
+				t.Fatalf("KWP %d 字节: 篡改第 %d 字节未被发现", n, i)
+			}
+		}
+	}
+
+	if _, err := WrapKey(kek, []byte{1}); err == nil {
+		t.Fatal("KW 应拒绝 1 字节输入")
+	}
+	// KW 只有 8 位校验，统计单字节篡改的漏检率应接近 1/256。
+	wrapped, _ := WrapKey(kek, material)
+	missed, total := 0, 0
+	for i := range wrapped {
+		for d := 1; d < 256; d++ {
+			tampered := append([]byte(nil), wrapped...)
+			tampered[i] ^= byte(d)
+			if _, err := UnwrapKey(kek, tampered); err == nil {
+				missed++
+			}
+			total++
+		}
+	}
+	if missed*64 > total {
+		t.Fatalf("KW 篡改漏检 %d/%d，明显高于 1/256", missed, total)
+	}
+}
@@ -0,0 +1,261 @@
+package saes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 字节级工作模式：每 2 个字节按大端组成一个 16 位分组。ECB 与 CBC 按 Padding 填充，
+// CTR、CFB、OFB 为流模式，末尾不足一个分组时截断密钥流，不使用填充。
+
+// Mode 为工作模式。
+type Mode string
+
+const (
+	ModeECB Mode = "ecb"
+	ModeCBC Mode = "cbc"
+	ModeCTR Mode = "ctr"
+	ModeCFB Mode = "cfb"
+	ModeOFB Mode = "ofb"
+)
+
+// Modes 列出全部支持的工作模式。
+var Modes = []Mode{ModeECB, ModeCBC, ModeCTR, ModeCFB, ModeOFB}
+
+// Padding 为 ECB 与 CBC 使用的填充方式。
+type Padding string
+
+const (
+	// PaddingPKCS7 补 1 个 0x01 或 2 个 0x02，解密时严格校验。
+	PaddingPKCS7 Padding = "pkcs7"
+	// PaddingZero 与 EncryptASCIIToBase64 相同：奇数长度时补一个 0x00，解密时去掉末尾的一个 0x00。
+	PaddingZero Padding = "zero"
+	// PaddingNone 要求数据长度为偶数。
+	PaddingNone Padding = "none"
+)
+
+// Paddings 列出全部支持的填充方式。
+var Paddings = []Padding{PaddingPKCS7, PaddingZero, PaddingNone}
+
+// ParseMode 解析工作模式名称（不区分大小写）。
+func ParseMode(s string) (Mode, error) {
+	for _, m := range Modes {
+		if strings.EqualFold(strings.TrimSpace(s), string(m)) {
+			return m, nil
+		}
+	}
+	return "", fmt.Errorf("不支持的工作模式: %s（可选 ecb、cbc、ctr、cfb、ofb）", s)
+}
+
+// ParsePadding 解析填充方式名称（不区分大小写），空字符串视为 pkcs7。
+func ParsePadding(s string) (Padding, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return PaddingPKCS7, nil
+	}
+	for _, p := range Paddings {
+		if strings.EqualFold(s, string(p)) {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("不支持的填充方式: %s（可选 pkcs7、zero、none）", s)
+}
+
+// NeedsIV 报告该模式是否使用初始向量（CTR 中为初始计数器）。
+func (m Mode) NeedsIV() bool {
+	return m != ModeECB
+}
+
+// Streaming 报告该模式是否为流模式（不使用填充）。
+func (m Mode) Streaming() bool {
+	return m == ModeCTR || m == ModeCFB || m == ModeOFB
+}
+
+// KeyedBlock 把分组实现与一个 16、32 或 48 位密钥绑定，后两者按二重/三重级联加密。
+type KeyedBlock struct {
+	bc   BlockCipher
+	keys []uint16
+}
+
+// ParseKey 解析 16/32/48 位二进制或 4/8/12 位十六进制（带 0x 前缀）密钥，返回级联使用的各 16 位子密钥。
+func ParseKey(key string) ([]uint16, error) {
+	_, keys, err := parseKey(key)
+	return keys, err
+}
+
+// NewKeyedBlock 解析 key 并与 bc 绑定。
+func NewKeyedBlock(bc BlockCipher, key string) (*KeyedBlock, error) {
+	keys, err := ParseKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("无法解析密钥: %w", err)
+	}
+	return &KeyedBlock{bc: bc, keys: keys}, nil
+}
+
+// EncryptBlock 依次用各子密钥加密一个分组。
+func (k *KeyedBlock) EncryptBlock(block uint16) uint16 {
+	return encryptWithKeys(k.bc, block, k.keys)
+}
+
+// DecryptBlock 按相反顺序用各子密钥解密一个分组。
+func (k *KeyedBlock) DecryptBlock(block uint16) uint16 {
+	return decryptWithKeys(k.bc, block, k.keys)
+}
+
+// KeyBits 返回密钥总位数（16、32 或 48）。
+func (k *KeyedBlock) KeyBits() int {
+	return 16 * len(k.keys)
+}
+
+// EncryptBytes 以指定模式加密任意长度的字节串；ECB 忽略 iv，流模式忽略 padding。
+func EncryptBytes(kb *KeyedBlock, mode Mode, padding Padding, iv uint16, plaintext []byte) ([]byte, error) {
+	st, err := newModeState(kb, mode, iv)
+	if err != nil {
+		return nil, err
+	}
+	data := plaintext
+	if !mode.Streaming() {
+		if data, err = pad(plaintext, padding); err != nil {
+			return nil, err
+		}
+	}
+	out := make([]byte, len(data))
+	st.process(out, data, true)
+	return out, nil
+}
+
+// DecryptBytes 为 EncryptBytes 的逆运算。
+func DecryptBytes(kb *KeyedBlock, mode Mode, padding Padding, iv uint16, ciphertext []byte) ([]byte, error) {
+	st, err := newModeState(kb, mode, iv)
+	if err != nil {
+		return nil, err
+	}
+	if !mode.Streaming() && len(ciphertext)%2 != 0 {
+		return nil, fmt.Errorf("密文字节长度必须是 2 的倍数")
+	}
+	out := make([]byte, len(ciphertext))
+	st.process(out, ciphertext, false)
+	if mode.Streaming() {
+		return out, nil
+	}
+	return unpad(out, padding)
+}
+
+// modeState 保存工作模式在分组之间传递的状态：CBC/CFB 的上一个密文分组、CTR 的计数器或 OFB 的输出。
+type modeState struct {
+	kb   *KeyedBlock
+	mode Mode
+	reg  uint16
+}
+
+func newModeState(kb *KeyedBlock, mode Mode, iv uint16) (*modeState, error) {
+	switch mode {
+	case ModeECB, ModeCBC, ModeCTR, ModeCFB, ModeOFB:
+		return &modeState{kb: kb, mode: mode, reg: iv}, nil
+	default:
+		return nil, fmt.Errorf("不支持的工作模式: %s", mode)
+	}
+}
+
+// process 逐分组变换 src 到 dst；流模式下末尾的单个字节只使用密钥流的高字节。
+func (s *modeState) process(dst, src []byte, encrypt bool) {
+	for i := 0; i+1 < len(src); i += 2 {
+		putBlock(dst[i:], s.block(getBlock(src[i:]), encrypt))
+	}
+	if len(src)%2 != 0 {
+		last := len(src) - 1
+		dst[last] = byte(s.block(uint16(src[last])<<8, encrypt) >> 8)
+	}
+}
+
+func (s *modeState) block(b uint16, encrypt bool) uint16 {
+	switch s.mode {
+	case ModeECB:
+		if encrypt {
+			return s.kb.EncryptBlock(b)
+		}
+		return s.kb.DecryptBlock(b)
+	case ModeCBC:
+		if encrypt {
+			s.reg = s.kb.EncryptBlock(b ^ s.reg)
+			return s.reg
+		}
+		p := s.kb.DecryptBlock(b) ^ s.reg
+		s.reg = b
+		return p
+	case ModeCTR:
+		ks := s.kb.EncryptBlock(s.reg)
+		s.reg++
+		return b ^ ks
+	case ModeOFB:
+		s.reg = s.kb.EncryptBlock(s.reg)
+		return b ^ s.reg
+	default: // ModeCFB
+		out := b ^ s.kb.EncryptBlock(s.reg)
+		if encrypt {
+			s.reg = out
+		} else {
+			s.reg = b
+		}
+		return out
+	}
+}
+
+func pad(data []byte, padding Padding) ([]byte, error) {
+	out := append([]byte(nil), data...)
+	switch padding {
+	case PaddingPKCS7:
+		n := 2 - len(data)%2
+		for i := 0; i < n; i++ {
+			out = append(out, byte(n))
+		}
+	case PaddingZero:
+		if len(out)%2 != 0 {
+			out = append(out, 0x00)
+		}
+	case PaddingNone:
+		if len(out)%2 != 0 {
+			return nil, fmt.Errorf("不使用填充时数据长度必须是 2 的倍数")
+		}
+	default:
+		return nil, fmt.Errorf("不支持的填充方式: %s", padding)
+	}
+	return out, nil
+}
+
+func unpad(data []byte, padding Padding) ([]byte, error) {
+	switch padding {
+	case PaddingPKCS7:
+		if len(data) == 0 {
+			return nil, fmt.Errorf("PKCS#7 填充无效：数据为空")
+		}
+		n := int(data[len(data)-1])
+		if n < 1 || n > 2 || n > len(data) {
+			return nil, fmt.Errorf("PKCS#7 填充无效")
+		}
+		for _, b := range data[len(data)-n:] {
+			if int(b) != n {
+				return nil, fmt.Errorf("PKCS#7 填充无效")
+			}
+		}
+		return data[:len(data)-n], nil
+	case PaddingZero:
+		if len(data) > 0 && data[len(data)-1] == 0x00 {
+			return data[:len(data)-1], nil
+		}
+		return data, nil
+	case PaddingNone:
+		return data, nil
+	default:
+		return nil, fmt.Errorf("不支持的填充方式: %s", padding)
+	}
+}
+
+func getBlock(b []byte) uint16 {
+	return uint16(b[0])<<8 | uint16(b[1])
+}
+
+func putBlock(b []byte, v uint16) {
+	b[0] = byte(v >> 8)
+	b[1] = byte(v)
+}
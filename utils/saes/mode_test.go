@@ -0,0 +1,54 @@
+package saes
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestModesRoundTrip(t *testing.T) {
+	kb, err := NewKeyedBlock(Standard, "0x123456789ABC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mode := range Modes {
+		for _, padding := range Paddings {
+			for n := 0; n <= 9; n++ {
+				plaintext := bytes.Repeat([]byte{0xA5, 0x01}, 5)[:n]
+				if padding == PaddingZero && n > 0 {
+					plaintext[n-1] = 0x7F // 零填充无法区分末尾的 0x00
+				}
+				ct, err := EncryptBytes(kb, mode, padding, 0xBEEF, plaintext)
+				if padding == PaddingNone && !mode.Streaming() && n%2 != 0 {
+					if err == nil {
+						t.Errorf("%s/%s: 奇数长度应当报错", mode, padding)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("%s/%s/%d: %v", mode, padding, n, err)
+				}
+				if mode.Streaming() && len(ct) != n {
+					t.Errorf("%s: 流模式密文长度 %d，期望 %d", mode, len(ct), n)
+				}
+				pt, err := DecryptBytes(kb, mode, padding, 0xBEEF, ct)
+				if err != nil || !bytes.Equal(pt, plaintext) {
+					t.Errorf("%s/%s/%d: 解密得到 %x, %v；期望 %x", mode, padding, n, pt, err, plaintext)
+				}
+			}
+		}
+	}
+}
+
+func TestPKCS7RejectsBadPadding(t *testing.T) {
+	kb, err := NewKeyedBlock(Standard, "0xA73B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, last := range []uint16{0x0000, 0x0003, 0x0102, 0x00FF} {
+		var ct [2]byte
+		putBlock(ct[:], kb.EncryptBlock(last))
+		if _, err := DecryptBytes(kb, ModeECB, PaddingPKCS7, 0, ct[:]); err == nil {
+			t.Errorf("末分组 0x%04X 的填充应当无效", last)
+		}
+	}
+}
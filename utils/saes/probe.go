@@ -0,0 +1,160 @@
+package saes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Step 标识轮函数中的一个步骤。
+type Step int
+
+const (
+	StepAddRoundKey Step = iota
+	StepSubNib
+	StepShiftRows
+	StepMixColumns
+)
+
+func (s Step) String() string {
+	switch s {
+	case StepAddRoundKey:
+		return "AddRoundKey"
+	case StepSubNib:
+		return "SubNib"
+	case StepShiftRows:
+		return "ShiftRows"
+	case StepMixColumns:
+		return "MixColumns"
+	default:
+		return fmt.Sprintf("Step(%d)", int(s))
+	}
+}
+
+// ParseStep 解析步骤名称（不区分大小写）。
+func ParseStep(name string) (Step, error) {
+	for s := StepAddRoundKey; s <= StepMixColumns; s++ {
+		if strings.EqualFold(strings.TrimSpace(name), s.String()) {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("未知的步骤: %s（可选 AddRoundKey、SubNib、ShiftRows、MixColumns）", name)
+}
+
+// Point 定位加密过程中的一个步骤，第 0 轮只有初始的 AddRoundKey。
+type Point struct {
+	Round int
+	Step  Step
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("round %d %s", p.Round, p.Step)
+}
+
+// Probe 在每个步骤执行之后被调用，返回值替换当前状态；原样返回即为只读观察。
+// 侧信道泄露建模、故障注入与逐步追踪都基于它实现。
+type Probe func(p Point, state uint16) uint16
+
+// Points 按执行顺序列出加密经过的全部步骤。
+func (c *Cipher) Points() []Point {
+	points := []Point{{Round: 0, Step: StepAddRoundKey}}
+	for r := 1; r <= c.params.Rounds; r++ {
+		points = append(points, Point{r, StepSubNib}, Point{r, StepShiftRows})
+		if c.mixesIn(r) {
+			points = append(points, Point{r, StepMixColumns})
+		}
+		points = append(points, Point{r, StepAddRoundKey})
+	}
+	return points
+}
+
+// EncryptBlockProbed 与 EncryptBlock 相同，但在每个步骤之后调用 probe。
+func (c *Cipher) EncryptBlockProbed(block, key uint16, probe Probe) uint16 {
+	roundKeys := c.expandKey(key)
+	state := uint16ToStateCore(block)
+	observe := func(p Point) {
+		state = uint16ToStateCore(probe(p, stateToUint16(state)))
+	}
+
+	state = addRoundKeyCore(state, roundKeys[0])
+	observe(Point{0, StepAddRoundKey})
+	for r := 1; r <= c.params.Rounds; r++ {
+		state = subNibCore(state, c.sBox)
+		observe(Point{r, StepSubNib})
+		state = shiftRowsCore(state)
+		observe(Point{r, StepShiftRows})
+		if c.mixesIn(r) {
+			state = c.mixColumns(state, c.mix)
+			observe(Point{r, StepMixColumns})
+		}
+		state = addRoundKeyCore(state, roundKeys[r])
+		observe(Point{r, StepAddRoundKey})
+	}
+
+	return stateToUint16(state)
+}
+
+// RoundKeys 返回全部轮密钥（按 16 位状态排列）。
+func (c *Cipher) RoundKeys(key uint16) []uint16 {
+	roundKeys := c.expandKey(key)
+	out := make([]uint16, len(roundKeys))
+	for i, rk := range roundKeys {
+		out[i] = stateToUint16(rk)
+	}
+	return out
+}
+
+// SBox 返回该变体的 S 盒。
+func (c *Cipher) SBox() [16]byte {
+	return c.sBox
+}
+
+// InvSBox 返回该变体的逆 S 盒。
+func (c *Cipher) InvSBox() [16]byte {
+	return c.invSBox
+}
+
+// StandardCipher 返回标准参数下的通用实现，可用于 EncryptBlockProbed 等需要 *Cipher 的场景。
+func StandardCipher() *Cipher {
+	return standardGeneric
+}
+
+var standardGeneric = mustNewCipher(DefaultParams())
+
+// ShiftRowsState 对 16 位状态执行 ShiftRows（自身即为逆运算）。
+func ShiftRowsState(state uint16) uint16 {
+	return stateToUint16(shiftRowsCore(uint16ToStateCore(state)))
+}
+
+// MixColumnsState 对 16 位状态执行该变体的列混淆。
+func (c *Cipher) MixColumnsState(state uint16) uint16 {
+	return stateToUint16(c.mixColumns(uint16ToStateCore(state), c.mix))
+}
+
+// InvMixColumnsState 对 16 位状态执行该变体的逆列混淆。
+func (c *Cipher) InvMixColumnsState(state uint16) uint16 {
+	return stateToUint16(c.mixColumns(uint16ToStateCore(state), c.invMix))
+}
+
+// PeelLastRound 用最后一轮轮密钥剥掉最后一轮，返回最后一次 SubNib 之前的状态。
+func (c *Cipher) PeelLastRound(block, lastRoundKey uint16) uint16 {
+	state := block ^ lastRoundKey
+	if c.mixesIn(c.params.Rounds) {
+		state = c.InvMixColumnsState(state)
+	}
+	state = ShiftRowsState(state)
+	return stateToUint16(subNibCore(uint16ToStateCore(state), c.invSBox))
+}
+
+// KeyFromRoundKey 由第 round 轮的轮密钥逆推主密钥：w[2i-1] = w[2i+1] ^ w[2i]，w[2i-2] = w[2i] ^ g(w[2i-1], RC_i)。
+func (c *Cipher) KeyFromRoundKey(roundKey uint16, round int) (uint16, error) {
+	if round < 0 || round > c.params.Rounds {
+		return 0, fmt.Errorf("轮数必须在 0 到 %d 之间", c.params.Rounds)
+	}
+	even := byte(roundKey >> 8)
+	odd := byte(roundKey)
+	for i := round; i >= 1; i-- {
+		odd ^= even
+		even ^= c.g(odd, c.rcon[i-1])
+	}
+	return uint16(even)<<8 | uint16(odd), nil
+}
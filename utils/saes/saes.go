@@ -16,6 +16,11 @@ var (
 
 // EncryptBinary 接受16位（二进制字符串）明文与密钥，返回16位密文字符串。
 func EncryptBinary(plaintext, key string) (string, error) {
+	return EncryptBinaryWith(Standard, plaintext, key)
+}
+
+// EncryptBinaryWith 使用指定的分组实现执行 EncryptBinary。
+func EncryptBinaryWith(bc BlockCipher, plaintext, key string) (string, error) {
 	pt, err := parseBinary16(plaintext)
 	if err != nil {
 		return "", fmt.Errorf("无法解析二进制明文: %w", err)
@@ -26,13 +31,18 @@ func EncryptBinary(plaintext, key string) (string, error) {
 		return "", fmt.Errorf("无法解析二进制密钥: %w", err)
 	}
 
-	block := encryptWithKeys(pt, keys)
+	block := encryptWithKeys(bc, pt, keys)
 	result := fmt.Sprintf("%016b", block)
 	return result, nil
 }
 
 // DecryptBinary 接受16位（二进制字符串）密文与密钥，返回16位明文字符串。
 func DecryptBinary(ciphertext, key string) (string, error) {
+	return DecryptBinaryWith(Standard, ciphertext, key)
+}
+
+// DecryptBinaryWith 使用指定的分组实现执行 DecryptBinary。
+func DecryptBinaryWith(bc BlockCipher, ciphertext, key string) (string, error) {
 	ct, err := parseBinary16(ciphertext)
 	if err != nil {
 		return "", fmt.Errorf("无法解析二进制密文: %w", err)
@@ -43,7 +53,7 @@ func DecryptBinary(ciphertext, key string) (string, error) {
 		return "", fmt.Errorf("无法解析二进制密钥: %w", err)
 	}
 
-	block := decryptWithKeys(ct, keys)
+	block := decryptWithKeys(bc, ct, keys)
 	result := fmt.Sprintf("%016b", block)
 	return result, nil
 }
@@ -168,24 +178,24 @@ func parseKey(key string) (string, []uint16, error) {
 	}
 }
 
-func encryptWithKeys(block uint16, keys []uint16) uint16 {
+func encryptWithKeys(bc BlockCipher, block uint16, keys []uint16) uint16 {
 	if len(keys) == 0 {
 		return block
 	}
 	out := block
 	for _, k := range keys {
-		out = encryptBlock(out, k)
+		out = bc.EncryptBlock(out, k)
 	}
 	return out
 }
 
-func decryptWithKeys(block uint16, keys []uint16) uint16 {
+func decryptWithKeys(bc BlockCipher, block uint16, keys []uint16) uint16 {
 	if len(keys) == 0 {
 		return block
 	}
 	out := block
 	for i := len(keys) - 1; i >= 0; i-- {
-		out = decryptBlock(out, keys[i])
+		out = bc.DecryptBlock(out, keys[i])
 	}
 	return out
 }
@@ -295,6 +305,11 @@ func gfMul(a, b byte) byte {
 
 // EncryptASCIIToBase64 将 ASCII 明文（按 2 字节分组）转换为 Base64 编码的密文。
 func EncryptASCIIToBase64(plaintext, key string) (string, error) {
+	return EncryptASCIIToBase64With(Standard, plaintext, key)
+}
+
+// EncryptASCIIToBase64With 使用指定的分组实现执行 EncryptASCIIToBase64。
+func EncryptASCIIToBase64With(bc BlockCipher, plaintext, key string) (string, error) {
 	if len(plaintext) == 0 {
 		return "", fmt.Errorf("明文不能为空")
 	}
@@ -321,7 +336,7 @@ func EncryptASCIIToBase64(plaintext, key string) (string, error) {
 		high := rawBytes[i]
 		low := rawBytes[i+1]
 		block := (uint16(high) << 8) | uint16(low)
-		enc := encryptWithKeys(block, keys)
+		enc := encryptWithKeys(bc, block, keys)
 		cipherBytes = append(cipherBytes, byte(enc>>8), byte(enc&0xFF))
 	}
 
@@ -331,6 +346,11 @@ func EncryptASCIIToBase64(plaintext, key string) (string, error) {
 
 // DecryptBase64ToASCII 将 Base64 编码的密文解密为 ASCII 明文。
 func DecryptBase64ToASCII(ciphertext, key string) (string, error) {
+	return DecryptBase64ToASCIIWith(Standard, ciphertext, key)
+}
+
+// DecryptBase64ToASCIIWith 使用指定的分组实现执行 DecryptBase64ToASCII。
+func DecryptBase64ToASCIIWith(bc BlockCipher, ciphertext, key string) (string, error) {
 	sanitizedCipher := strings.TrimSpace(ciphertext)
 	if sanitizedCipher == "" {
 		return "", fmt.Errorf("密文不能为空")
@@ -355,7 +375,7 @@ func DecryptBase64ToASCII(ciphertext, key string) (string, error) {
 		high := cipherBytes[i]
 		low := cipherBytes[i+1]
 		block := (uint16(high) << 8) | uint16(low)
-		dec := decryptWithKeys(block, keys)
+		dec := decryptWithKeys(bc, block, keys)
 		resultBytes = append(resultBytes, byte(dec>>8), byte(dec&0xFF))
 	}
 
@@ -376,23 +396,46 @@ func DecryptBase64ToASCII(ciphertext, key string) (string, error) {
 
 // EncryptASCIIToBase64CBC 使用 CBC 模式对 ASCII 明文进行加密，返回 Base64 编码的密文与随机初始向量。
 func EncryptASCIIToBase64CBC(plaintext, key string) (string, string, error) {
+	return EncryptASCIIToBase64CBCWith(Standard, plaintext, key)
+}
+
+// EncryptASCIIToBase64CBCWith 使用指定的分组实现执行 EncryptASCIIToBase64CBC。
+func EncryptASCIIToBase64CBCWith(bc BlockCipher, plaintext, key string) (string, string, error) {
+	iv, err := generateRandomBlock()
+	if err != nil {
+		return "", "", err
+	}
+	ivHex := fmt.Sprintf("0x%04X", iv)
+	cipherBase64, err := EncryptASCIIToBase64CBCWithIV(bc, plaintext, key, ivHex)
+	if err != nil {
+		return "", "", err
+	}
+	return cipherBase64, ivHex, nil
+}
+
+// EncryptASCIIToBase64CBCWithIV 使用调用方给定的初始向量执行 CBC 加密，便于复现已知答案测试向量。
+func EncryptASCIIToBase64CBCWithIV(bc BlockCipher, plaintext, key, iv string) (string, error) {
 	if len(plaintext) == 0 {
-		return "", "", fmt.Errorf("明文不能为空")
+		return "", fmt.Errorf("明文不能为空")
 	}
 	for _, r := range plaintext {
 		if r > 0x7F {
-			return "", "", fmt.Errorf("检测到非 ASCII 字符: %q", r)
+			return "", fmt.Errorf("检测到非 ASCII 字符: %q", r)
 		}
 	}
 
 	_, keys, err := parseKey(key)
 	if err != nil {
-		return "", "", fmt.Errorf("无法解析二进制密钥: %w", err)
+		return "", fmt.Errorf("无法解析二进制密钥: %w", err)
 	}
 
-	iv, err := generateRandomBlock()
+	sanitizedIV := strings.TrimSpace(iv)
+	if sanitizedIV == "" {
+		return "", fmt.Errorf("初始向量不能为空")
+	}
+	ivValue, err := parseBinary16(sanitizedIV)
 	if err != nil {
-		return "", "", err
+		return "", fmt.Errorf("无法解析初始向量: %w", err)
 	}
 
 	rawBytes := []byte(plaintext)
@@ -402,14 +445,14 @@ func EncryptASCIIToBase64CBC(plaintext, key string) (string, string, error) {
 	}
 
 	cipherBlocks := make([]uint16, 0, len(rawBytes)/2)
-	prev := iv
+	prev := ivValue
 
 	for i := 0; i < len(rawBytes); i += 2 {
 		high := rawBytes[i]
 		low := rawBytes[i+1]
 		block := (uint16(high) << 8) | uint16(low)
 		chainInput := block ^ prev
-		enc := encryptWithKeys(chainInput, keys)
+		enc := encryptWithKeys(bc, chainInput, keys)
 		cipherBlocks = append(cipherBlocks, enc)
 		prev = enc
 	}
@@ -419,13 +462,16 @@ func EncryptASCIIToBase64CBC(plaintext, key string) (string, string, error) {
 		cipherBytes = append(cipherBytes, byte(block>>8), byte(block&0xFF))
 	}
 
-	cipherBase64 := base64.StdEncoding.EncodeToString(cipherBytes)
-	ivHex := fmt.Sprintf("0x%04X", iv)
-	return cipherBase64, ivHex, nil
+	return base64.StdEncoding.EncodeToString(cipherBytes), nil
 }
 
 // DecryptBase64ToASCIICBC 使用 CBC 模式解密 Base64 编码的密文。
 func DecryptBase64ToASCIICBC(ciphertext, key, iv string) (string, error) {
+	return DecryptBase64ToASCIICBCWith(Standard, ciphertext, key, iv)
+}
+
+// DecryptBase64ToASCIICBCWith 使用指定的分组实现执行 DecryptBase64ToASCIICBC。
+func DecryptBase64ToASCIICBCWith(bc BlockCipher, ciphertext, key, iv string) (string, error) {
 	_, keys, err := parseKey(key)
 	if err != nil {
 		return "", fmt.Errorf("无法解析密钥: %w", err)
@@ -457,7 +503,7 @@ func DecryptBase64ToASCIICBC(ciphertext, key, iv string) (string, error) {
 
 	for i := 0; i < len(cipherBytes); i += 2 {
 		block := (uint16(cipherBytes[i]) << 8) | uint16(cipherBytes[i+1])
-		dec := decryptWithKeys(block, keys)
+		dec := decryptWithKeys(bc, block, keys)
 		plainBlock := dec ^ prev
 		resultBytes = append(resultBytes, byte(plainBlock>>8), byte(plainBlock&0xFF))
 		prev = block
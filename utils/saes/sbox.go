@@ -0,0 +1,164 @@
+package saes
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// SBoxReport 汇总 4 位 S 盒的密码学性质。
+type SBoxReport struct {
+	SBox      [16]byte
+	Bijective bool
+	// Inverse 仅在 Bijective 为 true 时有效。
+	Inverse [16]byte
+	// DDT[a][b] 为输入差分 a 产生输出差分 b 的次数。
+	DDT                    [16][16]int
+	DifferentialUniformity int
+	// LAT[a][b] 为 #{x : a·x = b·S(x)} - 8。
+	LAT             [16][16]int
+	Nonlinearity    int
+	AlgebraicDegree int
+	// CoordinateANF 为各输出位（从最高位 y3 到最低位 y0）的代数正规型。
+	CoordinateANF [4]string
+	FixedPoints   []int
+}
+
+// AnalyzeSBox 计算任意 4 位 S 盒的双射性、差分均匀度、非线性度、代数次数与不动点。
+func AnalyzeSBox(box [16]byte) SBoxReport {
+	report := SBoxReport{SBox: box}
+	for i := range report.SBox {
+		report.SBox[i] &= 0x0F
+	}
+	box = report.SBox
+
+	report.Inverse, report.Bijective = invertSBox(box)
+
+	for a := 0; a < 16; a++ {
+		for x := 0; x < 16; x++ {
+			b := box[x] ^ box[x^a]
+			report.DDT[a][b]++
+		}
+	}
+	for a := 1; a < 16; a++ {
+		for b := 0; b < 16; b++ {
+			if report.DDT[a][b] > report.DifferentialUniformity {
+				report.DifferentialUniformity = report.DDT[a][b]
+			}
+		}
+	}
+
+	maxBias := 0
+	for a := 0; a < 16; a++ {
+		for b := 0; b < 16; b++ {
+			count := 0
+			for x := 0; x < 16; x++ {
+				if parity4(byte(a)&byte(x)) == parity4(byte(b)&box[x]) {
+					count++
+				}
+			}
+			report.LAT[a][b] = count - 8
+			if b != 0 && absInt(report.LAT[a][b]) > maxBias {
+				maxBias = absInt(report.LAT[a][b])
+			}
+		}
+	}
+	report.Nonlinearity = 8 - maxBias
+
+	for bit := 0; bit < 4; bit++ {
+		anf := CoordinateANF(box, 3-bit)
+		report.CoordinateANF[bit] = formatANF(anf)
+		for m := 0; m < 16; m++ {
+			if anf[m] == 1 && bits.OnesCount8(uint8(m)) > report.AlgebraicDegree {
+				report.AlgebraicDegree = bits.OnesCount8(uint8(m))
+			}
+		}
+	}
+
+	for x := 0; x < 16; x++ {
+		if int(box[x]) == x {
+			report.FixedPoints = append(report.FixedPoints, x)
+		}
+	}
+	return report
+}
+
+// MixColumnsBranchNumber 计算列混淆矩阵在以 poly 为模的 GF(2^4) 上的分支数（2x2 矩阵最大为 3）。
+func MixColumnsBranchNumber(m [4]byte, poly byte) (int, error) {
+	if !IsIrreducible(poly) {
+		return 0, fmt.Errorf("多项式 %s 不是 GF(2) 上的 4 次既约多项式", FormatPoly(poly))
+	}
+	best := 4
+	for a := byte(0); a < 16; a++ {
+		for b := byte(0); b < 16; b++ {
+			if a == 0 && b == 0 {
+				continue
+			}
+			out0 := gfMulPoly(m[0], a, poly) ^ gfMulPoly(m[1], b, poly)
+			out1 := gfMulPoly(m[2], a, poly) ^ gfMulPoly(m[3], b, poly)
+			weight := nonZero(a) + nonZero(b) + nonZero(out0) + nonZero(out1)
+			if weight < best {
+				best = weight
+			}
+		}
+	}
+	return best, nil
+}
+
+// CoordinateANF 通过 Möbius 变换求 S 盒第 bit 个输出位的 ANF 系数，下标为单项式的变量掩码。
+func CoordinateANF(box [16]byte, bit int) [16]byte {
+	var f [16]byte
+	for x := 0; x < 16; x++ {
+		f[x] = (box[x] >> bit) & 0x1
+	}
+	for step := 1; step < 16; step <<= 1 {
+		for x := 0; x < 16; x++ {
+			if x&step != 0 {
+				f[x] ^= f[x^step]
+			}
+		}
+	}
+	return f
+}
+
+func formatANF(anf [16]byte) string {
+	terms := make([]string, 0, 16)
+	for m := 15; m >= 0; m-- {
+		if anf[m] == 0 {
+			continue
+		}
+		if m == 0 {
+			terms = append(terms, "1")
+			continue
+		}
+		var sb strings.Builder
+		for v := 3; v >= 0; v-- {
+			if m&(1<<v) != 0 {
+				fmt.Fprintf(&sb, "x%d", v)
+			}
+		}
+		terms = append(terms, sb.String())
+	}
+	if len(terms) == 0 {
+		return "0"
+	}
+	return strings.Join(terms, " + ")
+}
+
+func parity4(v byte) int {
+	return bits.OnesCount8(v&0x0F) & 1
+}
+
+func nonZero(v byte) int {
+	if v&0x0F != 0 {
+		return 1
+	}
+	return 0
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,414 @@
+package saes
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// 自描述容器格式（版本 1），所有多字节字段均为大端：
+//
+//	偏移  长度  字段
+//	0     4     魔数 "SAES"
+//	4     1     版本号，当前为 1
+//	5     1     工作模式：1=ECB 2=CBC 3=CTR 4=CFB 5=OFB
+//	6     1     填充方式：0=none 1=pkcs7 2=zero（流模式恒为 0）
+//	7     1     密钥位数：16、32 或 48
+//	8     1     标志位：bit0 末尾附带 CMAC 标签，bit1 含密钥 ID
+//	9     2     IV（CTR 中为初始计数器，ECB 为 0）
+//	11    1+n   （bit1 置位时）密钥 ID 长度 n 与 n 字节密钥 ID
+//	...         密文
+//	末尾  2     （bit0 置位时）对头部与密文计算的 CMAC 标签
+
+// ContainerMagic 为容器文件的魔数。
+const ContainerMagic = "SAES"
+
+// ContainerVersion 为当前写出的容器版本。
+const ContainerVersion = 1
+
+const (
+	flagMAC   = 1 << 0
+	flagKeyID = 1 << 1
+
+	headerFixedSize = 11
+	maxKeyIDLen     = 255
+	streamChunk     = 32 * 1024
+)
+
+// ErrMACMismatch 表示容器的 CMAC 校验失败；此前已读出的明文都不可信。
+var ErrMACMismatch = errors.New("saes: MAC 校验失败，密文或头部已被篡改")
+
+var modeCodes = []Mode{1: ModeECB, 2: ModeCBC, 3: ModeCTR, 4: ModeCFB, 5: ModeOFB}
+
+var paddingCodes = []Padding{0: PaddingNone, 1: PaddingPKCS7, 2: PaddingZero}
+
+// Header 为容器头部。
+type Header struct {
+	Version int
+	Mode    Mode
+	Padding Padding
+	KeyBits int
+	IV      uint16
+	KeyID   string
+	HasMAC  bool
+
+	raw []byte
+}
+
+// StreamOptions 控制 NewEncryptWriter 写出的容器。
+type StreamOptions struct {
+	Mode    Mode
+	Padding Padding
+	// IV 为 nil 时随机生成；ECB 忽略该字段。
+	IV *uint16
+	// KeyID 为可选的密钥标识，最长 255 字节，原样写入头部供解密方选择密钥。
+	KeyID string
+	// MACKey 不为 nil 时在末尾附带 CMAC 标签（先加密后认证），应与加密密钥不同。
+	MACKey *KeyedBlock
+}
+
+// EncryptWriter 把写入的明文加密后写到底层 Writer，Close 时输出填充与 MAC 标签。
+type EncryptWriter struct {
+	w       io.Writer
+	st      *modeState
+	padding Padding
+	mac     hash.Hash
+	pending []byte
+	buf     []byte
+	closed  bool
+}
+
+// NewEncryptWriter 立即写出容器头部，返回的 Writer 在 Close 之前不会输出最后一个分组。
+// Close 不会关闭 w。
+func NewEncryptWriter(w io.Writer, kb *KeyedBlock, opts StreamOptions) (*EncryptWriter, error) {
+	h := Header{
+		Version: ContainerVersion,
+		Mode:    opts.Mode,
+		Padding: opts.Padding,
+		KeyBits: kb.KeyBits(),
+		KeyID:   opts.KeyID,
+		HasMAC:  opts.MACKey != nil,
+	}
+	if h.Mode.Streaming() {
+		h.Padding = PaddingNone
+	}
+	if h.Mode.NeedsIV() {
+		if opts.IV != nil {
+			h.IV = *opts.IV
+		} else {
+			var b [2]byte
+			if _, err := rand.Read(b[:]); err != nil {
+				return nil, fmt.Errorf("生成初始向量失败: %w", err)
+			}
+			h.IV = getBlock(b[:])
+		}
+	}
+	raw, err := h.marshal()
+	if err != nil {
+		return nil, err
+	}
+	st, err := newModeState(kb, h.Mode, h.IV)
+	if err != nil {
+		return nil, err
+	}
+
+	ew := &EncryptWriter{w: w, st: st, padding: h.Padding}
+	if h.HasMAC {
+		ew.mac = NewCMAC(opts.MACKey)
+		ew.mac.Write(raw)
+	}
+	if _, err := w.Write(raw); err != nil {
+		return nil, err
+	}
+	return ew, nil
+}
+
+// Write 加密 p 中的完整分组并写出，不足一个分组的字节留到下一次 Write 或 Close。
+func (ew *EncryptWriter) Write(p []byte) (int, error) {
+	if ew.closed {
+		return 0, errors.New("saes: 向已关闭的 EncryptWriter 写入")
+	}
+	n := len(p)
+	if len(ew.pending) == 1 && len(p) > 0 {
+		ew.pending = append(ew.pending, p[0])
+		p = p[1:]
+		if err := ew.emit(ew.pending); err != nil {
+			return 0, err
+		}
+		ew.pending = ew.pending[:0]
+	}
+	for len(p) >= 2 {
+		chunk := p[:min(len(p)&^1, streamChunk)]
+		if err := ew.emit(chunk); err != nil {
+			return 0, err
+		}
+		p = p[len(chunk):]
+	}
+	ew.pending = append(ew.pending, p...)
+	return n, nil
+}
+
+// Close 处理末尾数据与填充，并在需要时写出 MAC 标签。
+func (ew *EncryptWriter) Close() error {
+	if ew.closed {
+		return nil
+	}
+	ew.closed = true
+
+	tail := ew.pending
+	if !ew.st.mode.Streaming() {
+		var err error
+		if tail, err = pad(tail, ew.padding); err != nil {
+			return err
+		}
+	}
+	if len(tail) > 0 {
+		if err := ew.emit(tail); err != nil {
+			return err
+		}
+	}
+	if ew.mac != nil {
+		if _, err := ew.w.Write(ew.mac.Sum(nil)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ew *EncryptWriter) emit(plain []byte) error {
+	ew.buf = append(ew.buf[:0], plain...)
+	ew.st.process(ew.buf, plain, true)
+	if ew.mac != nil {
+		ew.mac.Write(ew.buf)
+	}
+	_, err := ew.w.Write(ew.buf)
+	return err
+}
+
+// DecryptReader 从容器中流式读出明文。启用 MAC 时标签在读到末尾才能校验，
+// 校验失败时 Read 返回 ErrMACMismatch，调用方应丢弃此前读出的全部数据。
+type DecryptReader struct {
+	r       io.Reader
+	header  *Header
+	st      *modeState
+	mac     hash.Hash
+	holdTag int
+	// in 为已读入但尚未解密的密文，末尾保留 MAC 标签与（分组模式的）最后一个分组。
+	in    []byte
+	out   []byte
+	chunk []byte
+	err   error
+}
+
+// ReadHeader 读取并解析容器头部，可先据此（如 KeyID）选择密钥再调用 NewDecryptReaderFromHeader。
+func ReadHeader(r io.Reader) (*Header, error) {
+	fixed := make([]byte, headerFixedSize)
+	if _, err := io.ReadFull(r, fixed); err != nil {
+		return nil, fmt.Errorf("读取容器头部失败: %w", err)
+	}
+	if string(fixed[:4]) != ContainerMagic {
+		return nil, fmt.Errorf("不是 S-AES 容器（魔数不匹配）")
+	}
+	h := &Header{Version: int(fixed[4]), KeyBits: int(fixed[7]), IV: binary.BigEndian.Uint16(fixed[9:])}
+	if h.Version != ContainerVersion {
+		return nil, fmt.Errorf("不支持的容器版本 %d", h.Version)
+	}
+	if int(fixed[5]) >= len(modeCodes) || modeCodes[fixed[5]] == "" {
+		return nil, fmt.Errorf("未知的工作模式编号 %d", fixed[5])
+	}
+	h.Mode = modeCodes[fixed[5]]
+	if int(fixed[6]) >= len(paddingCodes) {
+		return nil, fmt.Errorf("未知的填充方式编号 %d", fixed[6])
+	}
+	h.Padding = paddingCodes[fixed[6]]
+	if h.KeyBits != 16 && h.KeyBits != 32 && h.KeyBits != 48 {
+		return nil, fmt.Errorf("无效的密钥位数 %d", h.KeyBits)
+	}
+	flags := fixed[8]
+	if flags&^(flagMAC|flagKeyID) != 0 {
+		return nil, fmt.Errorf("未知的标志位 0x%02X", flags)
+	}
+	h.HasMAC = flags&flagMAC != 0
+	h.raw = fixed
+	if flags&flagKeyID != 0 {
+		var n [1]byte
+		if _, err := io.ReadFull(r, n[:]); err != nil {
+			return nil, fmt.Errorf("读取密钥 ID 失败: %w", err)
+		}
+		id := make([]byte, n[0])
+		if _, err := io.ReadFull(r, id); err != nil {
+			return nil, fmt.Errorf("读取密钥 ID 失败: %w", err)
+		}
+		h.KeyID = string(id)
+		h.raw = append(append(h.raw, n[0]), id...)
+	}
+	return h, nil
+}
+
+// NewDecryptReader 读取容器头部并返回明文 Reader；macKey 不为 nil 时容器必须带 MAC，反之亦然。
+func NewDecryptReader(r io.Reader, kb, macKey *KeyedBlock) (*DecryptReader, error) {
+	h, err := ReadHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	return NewDecryptReaderFromHeader(r, h, kb, macKey)
+}
+
+// NewDecryptReaderFromHeader 在已由 ReadHeader 读出头部的 r 上继续解密。
+func NewDecryptReaderFromHeader(r io.Reader, h *Header, kb, macKey *KeyedBlock) (*DecryptReader, error) {
+	if kb.KeyBits() != h.KeyBits {
+		return nil, fmt.Errorf("容器使用 %d 位密钥，提供的密钥为 %d 位", h.KeyBits, kb.KeyBits())
+	}
+	st, err := newModeState(kb, h.Mode, h.IV)
+	if err != nil {
+		return nil, err
+	}
+	dr := &DecryptReader{r: r, header: h, st: st}
+	if h.HasMAC {
+		if macKey == nil {
+			return nil, fmt.Errorf("容器带有 MAC 标签，需要提供 MAC 密钥")
+		}
+		dr.mac = NewCMAC(macKey)
+		dr.mac.Write(h.raw)
+		dr.holdTag = CMACSize
+	} else if macKey != nil {
+		// 调用方期望认证时拒绝不带 MAC 的容器，防止清除标志位的降级攻击。
+		return nil, fmt.Errorf("容器未带 MAC 标签，但调用方要求校验 MAC")
+	}
+	return dr, nil
+}
+
+// Header 返回容器头部。
+func (dr *DecryptReader) Header() Header {
+	return *dr.header
+}
+
+// Read 实现 io.Reader。
+func (dr *DecryptReader) Read(p []byte) (int, error) {
+	for len(dr.out) == 0 {
+		if dr.err != nil {
+			return 0, dr.err
+		}
+		dr.fill()
+	}
+	n := copy(p, dr.out)
+	dr.out = dr.out[n:]
+	return n, nil
+}
+
+// fill 读入一段密文并解密其中可以确定不属于末尾的部分。
+func (dr *DecryptReader) fill() {
+	if dr.chunk == nil {
+		dr.chunk = make([]byte, streamChunk)
+	}
+	n, err := dr.r.Read(dr.chunk)
+	dr.in = append(dr.in, dr.chunk[:n]...)
+	if err == io.EOF {
+		dr.finish()
+		return
+	}
+	if err != nil {
+		dr.err = err
+		return
+	}
+
+	hold := dr.holdTag
+	if !dr.st.mode.Streaming() {
+		hold += 2
+	}
+	ready := max(len(dr.in)-hold, 0) &^ 1
+	if ready == 0 {
+		return
+	}
+	dr.out = dr.decrypt(dr.in[:ready])
+	dr.in = append(dr.in[:0], dr.in[ready:]...)
+}
+
+func (dr *DecryptReader) finish() {
+	body := dr.in
+	if dr.mac != nil {
+		if len(body) < CMACSize {
+			dr.err = fmt.Errorf("容器被截断：缺少 MAC 标签")
+			return
+		}
+		tag := body[len(body)-CMACSize:]
+		body = body[:len(body)-CMACSize]
+		dr.mac.Write(body)
+		if subtle.ConstantTimeCompare(dr.mac.Sum(nil), tag) != 1 {
+			dr.err = ErrMACMismatch
+			return
+		}
+	}
+	if !dr.st.mode.Streaming() && len(body)%2 != 0 {
+		dr.err = fmt.Errorf("密文字节长度必须是 2 的倍数")
+		return
+	}
+
+	plain := make([]byte, len(body))
+	dr.st.process(plain, body, false)
+	if !dr.st.mode.Streaming() {
+		var err error
+		if plain, err = unpad(plain, dr.header.Padding); err != nil {
+			dr.err = err
+			return
+		}
+	}
+	dr.out = plain
+	dr.in = nil
+	dr.err = io.EOF
+}
+
+func (dr *DecryptReader) decrypt(ct []byte) []byte {
+	if dr.mac != nil {
+		dr.mac.Write(ct)
+	}
+	plain := make([]byte, len(ct))
+	dr.st.process(plain, ct, false)
+	return plain
+}
+
+func (h *Header) marshal() ([]byte, error) {
+	var mode, padding byte
+	for i, m := range modeCodes {
+		if m != "" && m == h.Mode {
+			mode = byte(i)
+		}
+	}
+	if mode == 0 {
+		return nil, fmt.Errorf("不支持的工作模式: %s", h.Mode)
+	}
+	found := false
+	for i, p := range paddingCodes {
+		if p == h.Padding {
+			padding, found = byte(i), true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("不支持的填充方式: %s", h.Padding)
+	}
+	if len(h.KeyID) > maxKeyIDLen {
+		return nil, fmt.Errorf("密钥 ID 不能超过 %d 字节", maxKeyIDLen)
+	}
+
+	var flags byte
+	if h.HasMAC {
+		flags |= flagMAC
+	}
+	if h.KeyID != "" {
+		flags |= flagKeyID
+	}
+	var buf bytes.Buffer
+	buf.WriteString(ContainerMagic)
+	buf.Write([]byte{byte(h.Version), mode, padding, byte(h.KeyBits), flags, byte(h.IV >> 8), byte(h.IV)})
+	if h.KeyID != "" {
+		buf.WriteByte(byte(len(h.KeyID)))
+		buf.WriteString(h.KeyID)
+	}
+	h.raw = buf.Bytes()
+	return h.raw, nil
+}
@@ -0,0 +1,129 @@
+package saes
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestStreamMatchesEncryptBytes(t *testing.T) {
+	kb, err := NewKeyedBlock(Standard, "0x12345678")
+	if err != nil {
+		t.Fatal(err)
+	}
+	iv := uint16(0x0F0F)
+	plaintext := bytes.Repeat([]byte("streaming S-AES "), 5000)
+	for _, mode := range Modes {
+		for _, padding := range []Padding{PaddingPKCS7, PaddingZero} {
+			for _, n := range []int{0, 1, 2, 3, 4097, len(plaintext)} {
+				msg := plaintext[:n]
+				var buf bytes.Buffer
+				ew, err := NewEncryptWriter(&buf, kb, StreamOptions{Mode: mode, Padding: padding, IV: &iv, KeyID: "lab-key"})
+				if err != nil {
+					t.Fatal(err)
+				}
+				// 以不规则的长度分多次写入，覆盖跨分组边界的情况。
+				for rest := msg; len(rest) > 0; {
+					k := min(len(rest), 1+len(rest)%7)
+					if _, err := ew.Write(rest[:k]); err != nil {
+						t.Fatal(err)
+					}
+					rest = rest[k:]
+				}
+				if err := ew.Close(); err != nil {
+					t.Fatal(err)
+				}
+
+				h, err := ReadHeader(bytes.NewReader(buf.Bytes()))
+				if err != nil {
+					t.Fatal(err)
+				}
+				want, err := EncryptBytes(kb, mode, padding, iv, msg)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if body := buf.Bytes()[len(h.raw):]; !bytes.Equal(body, want) {
+					t.Fatalf("%s/%s/%d: 流式密文与 EncryptBytes 不一致", mode, padding, n)
+				}
+
+				dr, err := NewDecryptReader(iotest.OneByteReader(bytes.NewReader(buf.Bytes())), kb, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := io.ReadAll(dr)
+				if padding == PaddingZero && n > 0 && msg[n-1] == 0 {
+					continue
+				}
+				if err != nil || !bytes.Equal(got, msg) {
+					t.Fatalf("%s/%s/%d: 解密失败: %v", mode, padding, n, err)
+				}
+				if dr.Header().KeyID != "lab-key" {
+					t.Errorf("密钥 ID 为 %q", dr.Header().KeyID)
+				}
+			}
+		}
+	}
+}
+
+func TestStreamMAC(t *testing.T) {
+	kb, _ := NewKeyedBlock(Standard, "0xA73B")
+	macKey, _ := NewKeyedBlock(Standard, "0x4AF5")
+	var buf bytes.Buffer
+	ew, err := NewEncryptWriter(&buf, kb, StreamOptions{Mode: ModeCTR, MACKey: macKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("authenticated container")
+	ew.Write(msg)
+	if err := ew.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dr, err := NewDecryptReader(bytes.NewReader(buf.Bytes()), kb, macKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := io.ReadAll(dr); err != nil || !bytes.Equal(got, msg) {
+		t.Fatalf("解密失败: %q, %v", got, err)
+	}
+	if _, err := NewDecryptReader(bytes.NewReader(buf.Bytes()), kb, nil); err == nil {
+		t.Error("缺少 MAC 密钥时应当报错")
+	}
+
+	for _, pos := range []int{8, 9, headerFixedSize + 3, buf.Len() - 1} {
+		tampered := bytes.Clone(buf.Bytes())
+		tampered[pos] ^= 0x01
+		dr, err := NewDecryptReader(bytes.NewReader(tampered), kb, macKey)
+		if err != nil {
+			continue // 头部被改坏时可能在解析阶段就被拒绝
+		}
+		if _, err := io.ReadAll(dr); !errors.Is(err, ErrMACMismatch) {
+			t.Errorf("篡改第 %d 字节后得到 %v，期望 ErrMACMismatch", pos, err)
+		}
+	}
+}
+
+func TestCMAC(t *testing.T) {
+	kb, _ := NewKeyedBlock(Standard, "0x2D55")
+	tags := map[string]bool{}
+	for _, msg := range []string{"", "a", "ab", "abc", "abcd"} {
+		m := NewCMAC(kb)
+		m.Write([]byte(msg))
+		tag := m.Sum(nil)
+		if tags[string(tag)] {
+			t.Errorf("消息 %q 的标签与之前的消息重复", msg)
+		}
+		tags[string(tag)] = true
+
+		// 分字节写入与一次写入结果相同。
+		m.Reset()
+		for i := range msg {
+			m.Write([]byte{msg[i]})
+		}
+		if !bytes.Equal(m.Sum(nil), tag) {
+			t.Errorf("消息 %q 分段写入的标签不同", msg)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package saeshash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"S-AES/utils/saes"
+)
+
+func TestVectors(t *testing.T) {
+	// 回归向量：由本实现生成，用于发现输出的意外变化；填充与压缩函数由 TestByHand 逐步核对。
+	cases := []struct {
+		c     Construction
+		bits  int
+		input string
+		want  string
+	}{
+		{DaviesMeyer, 16, "", "363d"},
+		{DaviesMeyer, 16, "abc", "1fd8"},
+		{DaviesMeyer, 32, "The quick brown fox", "8e9cf7ce"},
+		{MatyasMeyerOseas, 16, "abc", "6163"},
+		{MatyasMeyerOseas, 32, "abc", "c6ccbfa5"},
+		{MiyaguchiPreneel, 16, "", "290c"},
+		{MiyaguchiPreneel, 32, "The quick brown fox", "e2f9e848"},
+	}
+	for _, tc := range cases {
+		got, err := Sum(Config{Construction: tc.c, Bits: tc.bits}, []byte(tc.input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(got) != tc.want {
+			t.Fatalf("%s-%d(%q) = %x，期望 %s", tc.c, tc.bits, tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestByHand(t *testing.T) {
+	e := saes.EncryptBlockRaw // e(分组, 密钥)
+	// 空消息：0x80，1+8 为奇数再补一个 0x00，最后是 64 位比特长度 0，共 5 个分组。
+	h := uint16(0x6A09)
+	for _, m := range []uint16{0x8000, 0, 0, 0, 0} {
+		h = e(h, m) ^ h // Davies–Meyer：消息分组作密钥
+	}
+	if got, _ := Sum(Config{Construction: DaviesMeyer, Bits: 16}, nil); !bytes.Equal(got, []byte{byte(h >> 8), byte(h)}) {
+		t.Fatalf("DM-16(\"\") = %x，逐步计算为 %04x", got, h)
+	}
+
+	// "a"：61 80，1+1+8 为偶数不再补零，比特长度 8 占最后一个分组。
+	blocks := []uint16{0x6180, 0, 0, 0, 0x0008}
+	h = 0x6A09
+	for _, m := range blocks {
+		h = e(m, h) ^ m ^ h // Miyaguchi–Preneel：链接值作密钥
+	}
+	if got, _ := Sum(Config{Construction: MiyaguchiPreneel, Bits: 16}, []byte("a")); !bytes.Equal(got, []byte{byte(h >> 8), byte(h)}) {
+		t.Fatalf("MP-16(\"a\") = %x，逐步计算为 %04x", got, h)
+	}
+
+	// 32 位：两条链各自做 Davies–Meyer，每个分组之后交换低字节。
+	h1, h2 := uint16(0x6A09), uint16(0xBB67)
+	for _, m := range blocks {
+		a, b := e(h1, m)^h1, e(h2, m)^h2
+		h1, h2 = a&0xFF00|b&0x00FF, b&0xFF00|a&0x00FF
+	}
+	want := []byte{byte(h1 >> 8), byte(h1), byte(h2 >> 8), byte(h2)}
+	if got, _ := Sum(Config{Construction: DaviesMeyer, Bits: 32}, []byte("a")); !bytes.Equal(got, want) {
+		t.Fatalf("DM-32(\"a\") = %x，逐步计算为 %x", got, want)
+	}
+}
+
+func TestIncrementalWrite(t *testing.T) {
+	h, err := New(Config{Construction: MiyaguchiPreneel, Bits: 32})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("The quick brown fox")
+	for _, b := range msg {
+		h.Write([]byte{b})
+	}
+	first := h.Sum(nil)
+	// Sum 不改变内部状态，可以继续写入。
+	if again := h.Sum(nil); !bytes.Equal(first, again) {
+		t.Fatal("重复调用 Sum 的结果不同")
+	}
+	want, _ := Sum(Config{Construction: MiyaguchiPreneel, Bits: 32}, msg)
+	if !bytes.Equal(first, want) || h.Size() != 4 || h.BlockSize() != BlockSize {
+		t.Fatalf("逐字节写入得到 %x，期望 %x", first, want)
+	}
+}
+
+func TestCollisionAndSecondPreimage(t *testing.T) {
+	cfg := Config{Construction: DaviesMeyer, Bits: 16}
+	col, err := FindCollision(cfg, []byte("msg-"), 1<<17)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !col.Found || bytes.Equal(col.First, col.Second) {
+		t.Fatalf("16 位哈希应在 2^17 次内找到碰撞: %+v", col)
+	}
+	a, _ := Sum(cfg, col.First)
+	b, _ := Sum(cfg, col.Second)
+	if !bytes.Equal(a, b) || !bytes.Equal(a, col.Digest) {
+		t.Fatalf("碰撞消息的摘要不同: %x %x", a, b)
+	}
+
+	sp, err := FindSecondPreimage(cfg, []byte("pay alice 100"), 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sp.Found {
+		t.Fatalf("16 位哈希应在 2^20 次内找到第二原像，尝试 %d 次", sp.Attempts)
+	}
+	got, _ := Sum(cfg, sp.Message)
+	if !bytes.Equal(got, sp.Digest) || bytes.Equal(sp.Message, sp.Target) {
+		t.Fatalf("第二原像无效: %x", sp.Message)
+	}
+
+	if _, err := New(Config{Construction: "md5", Bits: 16}); err == nil {
+		t.Fatal("未知构造应报错")
+	}
+}
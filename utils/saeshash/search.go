@@ -0,0 +1,138 @@
+package saeshash
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Collision 为生日攻击的结果：候选消息为 prefix 后接 4 字节大端计数器。
+type Collision struct {
+	Found    bool
+	First    []byte
+	Second   []byte
+	Digest   []byte
+	Attempts int
+	// Expected 为找到第一对碰撞的期望尝试次数 √(π/2 · 2^n)。
+	Expected float64
+}
+
+// SecondPreimage 为第二原像搜索的结果。
+type SecondPreimage struct {
+	Found    bool
+	Target   []byte
+	Message  []byte
+	Digest   []byte
+	Attempts int
+	// Expected 为期望尝试次数 2^n。
+	Expected float64
+}
+
+// FindCollision 依次哈希 prefix‖0, prefix‖1, …，记录每个摘要首次出现的计数器，
+// 直到两条不同消息得到相同摘要或用完 maxAttempts 次尝试。
+func FindCollision(cfg Config, prefix []byte, maxAttempts int) (*Collision, error) {
+	s, err := newSearcher(cfg, prefix, 4, maxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	res := &Collision{Expected: math.Sqrt(math.Pi / 2 * math.Exp2(float64(cfg.Bits)))}
+	seen := make(map[uint32]uint32)
+	for i := 0; i < maxAttempts; i++ {
+		sum := s.sum(uint32(i))
+		res.Attempts = i + 1
+		if j, ok := seen[sum]; ok {
+			res.Found = true
+			res.First = s.message(j)
+			res.Second = s.message(uint32(i))
+			res.Digest = s.digestBytes(sum)
+			return res, nil
+		}
+		seen[sum] = uint32(i)
+	}
+	return res, nil
+}
+
+// FindSecondPreimage 寻找与 target 摘要相同、内容不同的消息：保持 target 的长度（从而 MD 填充相同），
+// 把最后至多 4 个字节替换为计数器。长度不同的消息填充不同，末尾固定的填充分组会缩小可达的摘要集合，
+// 可能根本无法命中目标。
+func FindSecondPreimage(cfg Config, target []byte, maxAttempts int) (*SecondPreimage, error) {
+	if len(target) == 0 {
+		return nil, fmt.Errorf("目标消息不能为空")
+	}
+	width := min(len(target), 4)
+	if limit := uint64(1) << (8 * width); uint64(maxAttempts) > limit {
+		maxAttempts = int(limit)
+	}
+	s, err := newSearcher(cfg, target[:len(target)-width], width, maxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	want, err := Sum(cfg, target)
+	if err != nil {
+		return nil, err
+	}
+	res := &SecondPreimage{Target: target, Digest: want, Expected: math.Exp2(float64(cfg.Bits))}
+	for i := 0; i < maxAttempts; i++ {
+		sum := s.sum(uint32(i))
+		res.Attempts = i + 1
+		if bytes.Equal(s.digestBytes(sum), want) {
+			if msg := s.message(uint32(i)); !bytes.Equal(msg, target) {
+				res.Found = true
+				res.Message = msg
+				return res, nil
+			}
+		}
+	}
+	return res, nil
+}
+
+// searcher 生成 prefix 后接 width 字节大端计数器的候选消息。
+type searcher struct {
+	d     *digest
+	buf   []byte
+	width int
+	out   []byte
+}
+
+func newSearcher(cfg Config, prefix []byte, width, maxAttempts int) (*searcher, error) {
+	h, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if maxAttempts < 1 || uint64(maxAttempts) > math.MaxUint32 {
+		return nil, fmt.Errorf("尝试次数必须在 1 到 %d 之间", uint64(math.MaxUint32))
+	}
+	buf := append(append([]byte(nil), prefix...), make([]byte, width)...)
+	return &searcher{d: h.(*digest), buf: buf, width: width}, nil
+}
+
+func (s *searcher) message(i uint32) []byte {
+	msg := append([]byte(nil), s.buf...)
+	s.putCounter(msg, i)
+	return msg
+}
+
+func (s *searcher) sum(i uint32) uint32 {
+	s.putCounter(s.buf, i)
+	s.d.Reset()
+	s.d.Write(s.buf)
+	s.out = s.d.Sum(s.out[:0])
+	if len(s.out) == 2 {
+		return uint32(binary.BigEndian.Uint16(s.out))
+	}
+	return binary.BigEndian.Uint32(s.out)
+}
+
+func (s *searcher) putCounter(msg []byte, i uint32) {
+	for k := 0; k < s.width; k++ {
+		msg[len(msg)-1-k] = byte(i >> (8 * k))
+	}
+}
+
+func (s *searcher) digestBytes(sum uint32) []byte {
+	if s.d.cfg.Bits == 16 {
+		return binary.BigEndian.AppendUint16(nil, uint16(sum))
+	}
+	return binary.BigEndian.AppendUint32(nil, sum)
+}
@@ -0,0 +1,123 @@
+package sca
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+
+	"S-AES/utils/saes"
+)
+
+// NibbleResult 为对第一轮轮密钥某个半字节的攻击结果。
+type NibbleResult struct {
+	Nibble      int
+	Best        byte
+	Correct     byte
+	Correlation float64
+	// Scores[g] 为猜测 g 在所有采样点上的最大 |ρ|。
+	Scores [16]float64
+	// Rank 为正确猜测的排名，1 表示排在第一。
+	Rank int
+}
+
+// RankPoint 记录使用前 Traces 条曲线时各半字节正确密钥的排名。
+type RankPoint struct {
+	Traces int
+	Ranks  [4]int
+}
+
+// CPAResult 为一次相关功耗分析的结果。
+type CPAResult struct {
+	Traces    int
+	Model     Model
+	Recovered uint16
+	Nibbles   [4]NibbleResult
+	Ranks     []RankPoint
+	// TracesToSuccess 为所有半字节都排名第一且此后保持不变所需的最少曲线数，0 表示未成功。
+	TracesToSuccess int
+}
+
+// CPA 针对第 1 轮 SubNib 输出做相关功耗分析，逐半字节恢复第一轮轮密钥（即主密钥）。
+// correctKey 仅用于统计排名，不参与攻击本身。
+func CPA(c *saes.Cipher, set *TraceSet, model Model, correctKey uint16) (*CPAResult, error) {
+	if set == nil || len(set.Traces) < 2 {
+		return nil, fmt.Errorf("至少需要 2 条曲线")
+	}
+
+	res := &CPAResult{Traces: len(set.Traces), Model: model}
+	for _, n := range checkpoints(len(set.Traces)) {
+		point := RankPoint{Traces: n}
+		for nib := 0; nib < 4; nib++ {
+			nr := attackNibble(c, set, model, nib, n, nibble(correctKey, nib))
+			point.Ranks[nib] = nr.Rank
+			if n == len(set.Traces) {
+				res.Nibbles[nib] = nr
+				res.Recovered |= uint16(nr.Best) << (12 - 4*nib)
+			}
+		}
+		res.Ranks = append(res.Ranks, point)
+	}
+
+	for i := len(res.Ranks) - 1; i >= 0; i-- {
+		if res.Ranks[i].Ranks != [4]int{1, 1, 1, 1} {
+			break
+		}
+		res.TracesToSuccess = res.Ranks[i].Traces
+	}
+	return res, nil
+}
+
+func attackNibble(c *saes.Cipher, set *TraceSet, model Model, nib, n int, correct byte) NibbleResult {
+	box := c.SBox()
+	nr := NibbleResult{Nibble: nib, Correct: correct}
+	hyp := make([]float64, n)
+	column := make([]float64, n)
+
+	for g := 0; g < 16; g++ {
+		for i := 0; i < n; i++ {
+			x := nibble(set.Plaintexts[i], nib) ^ byte(g)
+			y := box[x]
+			if model == HammingDistance {
+				hyp[i] = float64(bits.OnesCount8(x ^ y))
+			} else {
+				hyp[i] = float64(bits.OnesCount8(y))
+			}
+		}
+		for j := range set.Points {
+			for i := 0; i < n; i++ {
+				column[i] = set.Traces[i][j]
+			}
+			if rho := math.Abs(pearson(hyp, column)); rho > nr.Scores[g] {
+				nr.Scores[g] = rho
+			}
+		}
+	}
+
+	order := make([]int, 16)
+	for g := range order {
+		order[g] = g
+	}
+	sort.SliceStable(order, func(a, b int) bool { return nr.Scores[order[a]] > nr.Scores[order[b]] })
+	nr.Best = byte(order[0])
+	nr.Correlation = nr.Scores[order[0]]
+	for rank, g := range order {
+		if byte(g) == correct {
+			nr.Rank = rank + 1
+		}
+	}
+	return nr
+}
+
+// checkpoints 生成 10, 20, 50, 100, 200, 500 ... 直到 total 的曲线数序列。
+func checkpoints(total int) []int {
+	var out []int
+	for base := 10; base < total; base *= 10 {
+		for _, m := range []int{1, 2, 5} {
+			if n := base * m; n < total {
+				out = append(out, n)
+			}
+		}
+	}
+	return append(out, total)
+}
@@ -0,0 +1,49 @@
+package sca
+
+import (
+	"testing"
+
+	"S-AES/utils/saes"
+)
+
+func TestCPARecoversKey(t *testing.T) {
+	const key = 0xA73B
+	for _, model := range []Model{HammingWeight, HammingDistance} {
+		set, err := Generate(saes.StandardCipher(), Config{Key: key, Count: 2000, Noise: 1, Model: model, Seed: 42})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(set.Traces) != 2000 || len(set.Traces[0]) != len(set.Points) {
+			t.Fatalf("%s: 曲线形状不符", model)
+		}
+		res, err := CPA(saes.StandardCipher(), set, model, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Recovered != key || res.TracesToSuccess == 0 {
+			t.Fatalf("%s: 恢复出 %04X，期望 %04X（成功所需曲线 %d）", model, res.Recovered, key, res.TracesToSuccess)
+		}
+		for _, nr := range res.Nibbles {
+			if nr.Rank != 1 {
+				t.Fatalf("%s: 半字节 %d 的正确猜测排名 %d", model, nr.Nibble, nr.Rank)
+			}
+		}
+	}
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	cfg := Config{Key: 0x2D55, Count: 10, Noise: 0.5, Model: HammingWeight, Seed: 7}
+	a, _ := Generate(saes.StandardCipher(), cfg)
+	b, _ := Generate(saes.StandardCipher(), cfg)
+	for i := range a.Traces {
+		if a.Plaintexts[i] != b.Plaintexts[i] || a.Traces[i][0] != b.Traces[i][0] {
+			t.Fatal("相同种子应生成相同曲线")
+		}
+		if a.Ciphertexts[i] != saes.EncryptBlockRaw(a.Plaintexts[i], 0x2D55) {
+			t.Fatal("密文与明文不对应")
+		}
+	}
+	if _, err := CPA(saes.StandardCipher(), &TraceSet{Traces: [][]float64{{1}}}, HammingWeight, 0); err == nil {
+		t.Fatal("少于 2 条曲线应报错")
+	}
+}
@@ -0,0 +1,111 @@
+package sca
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// WriteCSV 输出带表头的 CSV：plaintext, ciphertext, 各采样点功耗。
+func WriteCSV(w io.Writer, set *TraceSet) error {
+	cw := csv.NewWriter(w)
+	header := []string{"plaintext", "ciphertext"}
+	for _, p := range set.Points {
+		header = append(header, p.String())
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	row := make([]string, len(header))
+	for i, trace := range set.Traces {
+		row[0] = fmt.Sprintf("0x%04X", set.Plaintexts[i])
+		row[1] = fmt.Sprintf("0x%04X", set.Ciphertexts[i])
+		for j, v := range trace {
+			row[2+j] = strconv.FormatFloat(v, 'f', 6, 64)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteNPY 以 NumPy .npy（v1.0, float64 小端）格式输出曲线矩阵，形状为 (条数, 采样点数)。
+func WriteNPY(w io.Writer, set *TraceSet) error {
+	data := make([]float64, 0, len(set.Traces)*len(set.Points))
+	for _, trace := range set.Traces {
+		data = append(data, trace...)
+	}
+	return writeNPY(w, "<f8", []int{len(set.Traces), len(set.Points)}, func(w io.Writer) error {
+		buf := make([]byte, 8)
+		for _, v := range data {
+			binary.LittleEndian.PutUint64(buf, math.Float64bits(v))
+			if _, err := w.Write(buf); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// WriteNPZ 输出包含 traces.npy、plaintexts.npy、ciphertexts.npy 的 .npz 压缩包，可直接 numpy.load。
+func WriteNPZ(w io.Writer, set *TraceSet) error {
+	zw := zip.NewWriter(w)
+	f, err := zw.Create("traces.npy")
+	if err != nil {
+		return err
+	}
+	if err := WriteNPY(f, set); err != nil {
+		return err
+	}
+	for name, values := range map[string][]uint16{
+		"plaintexts.npy":  set.Plaintexts,
+		"ciphertexts.npy": set.Ciphertexts,
+	} {
+		f, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		err = writeNPY(f, "<u2", []int{len(values)}, func(w io.Writer) error {
+			return binary.Write(w, binary.LittleEndian, values)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writeNPY(w io.Writer, descr string, shape []int, body func(io.Writer) error) error {
+	dims := make([]string, len(shape))
+	for i, d := range shape {
+		dims[i] = strconv.Itoa(d)
+	}
+	shapeStr := strings.Join(dims, ", ")
+	if len(shape) == 1 {
+		shapeStr += ","
+	}
+	header := fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': (%s), }", descr, shapeStr)
+	// magic(6) + 版本(2) + 头长度(2) + 头部 + '\n' 需按 64 字节对齐。
+	total := 10 + len(header) + 1
+	if pad := (64 - total%64) % 64; pad > 0 {
+		header += strings.Repeat(" ", pad)
+	}
+	header += "\n"
+
+	prefix := []byte{0x93, 'N', 'U', 'M', 'P', 'Y', 1, 0, 0, 0}
+	binary.LittleEndian.PutUint16(prefix[8:], uint16(len(header)))
+	if _, err := w.Write(prefix); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+	return body(w)
+}